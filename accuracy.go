@@ -0,0 +1,346 @@
+package blackjack
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/mbarley333/cards"
+)
+
+type Strategy int
+
+const (
+	StrategyBasic Strategy = iota
+	StrategyCount
+)
+
+var StrategyMap = map[Strategy]string{
+	StrategyBasic: "Basic",
+	StrategyCount: "Count",
+}
+
+func (s Strategy) String() string {
+	return StrategyMap[s]
+}
+
+var StrategyInputMap = map[string]Strategy{
+	"basic": StrategyBasic,
+	"count": StrategyCount,
+}
+
+var StrategyActionMap = map[Strategy]func(io.Writer, io.Reader, *Player, cards.Card, int, CardCounter, Stage) Action{
+	StrategyBasic: AiActionBasic,
+	StrategyCount: AiActionCount,
+}
+
+type HandCategory int
+
+const (
+	CategoryHard HandCategory = iota
+	CategorySoft
+	CategoryPair
+)
+
+var HandCategoryMap = map[HandCategory]string{
+	CategoryHard: "hard",
+	CategorySoft: "soft",
+	CategoryPair: "pairs",
+}
+
+func (h HandCategory) String() string {
+	return HandCategoryMap[h]
+}
+
+// Category sorts a hand into hard totals, soft totals or pairs
+func (h Hand) Category() HandCategory {
+	if len(h.Cards) == 2 && h.Cards[0].Rank == h.Cards[1].Rank {
+		return CategoryPair
+	}
+	if h.Score() != h.MinScore() {
+		return CategorySoft
+	}
+	return CategoryHard
+}
+
+// Cell names the strategy chart cell of the hand against the upcard,
+// such as "hard 16 v 10" or "pair of 8s v A"
+func (h Hand) Cell(upcard cards.Card) string {
+
+	dealer := strconv.Itoa(ScoreDealerHoleCard(upcard))
+	if upcard.Rank == cards.Ace {
+		dealer = "A"
+	}
+
+	switch h.Category() {
+	case CategoryPair:
+		value := strconv.Itoa(min(int(h.Cards[0].Rank), 10))
+		if h.Cards[0].Rank == cards.Ace {
+			value = "A"
+		}
+		return "pair of " + value + "s v " + dealer
+	case CategorySoft:
+		return "soft " + strconv.Itoa(h.Score()) + " v " + dealer
+	}
+
+	return "hard " + strconv.Itoa(h.Score()) + " v " + dealer
+}
+
+// Mistake is a decision that differed from the strategy.  Cost is the
+// expected value given up, in bets
+type Mistake struct {
+	Round    int
+	Player   string
+	Cell     string
+	Category HandCategory
+	Action   Action
+	Correct  Action
+	Cost     float64
+}
+
+type CategoryAccuracy struct {
+	Decisions int     `json:"decisions"`
+	Correct   int     `json:"correct"`
+	EvLost    float64 `json:"evLost"`
+}
+
+// Accuracy is how closely a player's decisions followed the strategy.
+// Mistakes are only kept for the session
+type Accuracy struct {
+	Decisions  int                          `json:"decisions"`
+	Correct    int                          `json:"correct"`
+	EvLost     float64                      `json:"evLost"`
+	Categories map[string]*CategoryAccuracy `json:"categories"`
+	Mistakes   []Mistake                    `json:"-"`
+}
+
+// Add counts a decision in the hand category
+func (a *Accuracy) Add(category HandCategory, correct bool, cost float64) {
+
+	if a.Categories == nil {
+		a.Categories = map[string]*CategoryAccuracy{}
+	}
+	c, ok := a.Categories[category.String()]
+	if !ok {
+		c = &CategoryAccuracy{}
+		a.Categories[category.String()] = c
+	}
+
+	a.Decisions++
+	c.Decisions++
+	if correct {
+		a.Correct++
+		c.Correct++
+	}
+	a.EvLost += cost
+	c.EvLost += cost
+}
+
+func (a Accuracy) Percent() float64 {
+	if a.Decisions == 0 {
+		return 0
+	}
+	return float64(a.Correct) / float64(a.Decisions) * 100
+}
+
+func (a Accuracy) Summary() string {
+	return fmt.Sprintf("%d of %d decisions correct (%.1f%%), EV lost %.2f bets", a.Correct, a.Decisions, a.Percent(), a.EvLost)
+}
+
+// Report lists the hand categories worst first, then the chart cells
+// where the most EV was lost
+func (a Accuracy) Report(output io.Writer) {
+
+	fmt.Fprintln(output, "Decision accuracy: "+a.Summary())
+	if a.Decisions == 0 {
+		return
+	}
+
+	names := []string{}
+	for name := range a.Categories {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return a.Categories[names[i]].EvLost > a.Categories[names[j]].EvLost
+	})
+
+	fmt.Fprintln(output, "Leaks by hand category:")
+	for _, name := range names {
+		c := a.Categories[name]
+		fmt.Fprintf(output, "  %-5s %d of %d correct, EV lost %.2f bets\n", name, c.Correct, c.Decisions, c.EvLost)
+	}
+
+	if len(a.Mistakes) == 0 {
+		return
+	}
+
+	fmt.Fprintln(output, "Worst leaks:")
+	for _, leak := range a.Leaks(5) {
+		fmt.Fprintf(output, "  %s: %d mistakes, EV lost %.2f bets\n", leak.Cell, leak.Count, leak.Cost)
+	}
+
+	fmt.Fprintln(output, "Mistakes:")
+	for _, m := range a.Mistakes {
+		fmt.Fprintf(output, "  round %d, %s: chose %s, correct %s, cost %.3f bets\n", m.Round, m.Cell, m.Action, m.Correct, m.Cost)
+	}
+}
+
+// Leak is the mistakes made in one chart cell
+type Leak struct {
+	Cell  string
+	Count int
+	Cost  float64
+}
+
+// Leaks groups the mistakes by chart cell, costliest first, keeping at
+// most n of them
+func (a Accuracy) Leaks(n int) []Leak {
+
+	leaks := []Leak{}
+	index := map[string]int{}
+	for _, m := range a.Mistakes {
+		i, ok := index[m.Cell]
+		if !ok {
+			i = len(leaks)
+			index[m.Cell] = i
+			leaks = append(leaks, Leak{Cell: m.Cell})
+		}
+		leaks[i].Count++
+		leaks[i].Cost += m.Cost
+	}
+
+	sort.SliceStable(leaks, func(i, j int) bool {
+		return leaks[i].Cost > leaks[j].Cost
+	})
+	if len(leaks) > n {
+		leaks = leaks[:n]
+	}
+
+	return leaks
+}
+
+// WithStrategy sets the strategy human decisions are checked against
+func WithStrategy(strategy Strategy) Option {
+	return func(g *Game) error {
+		if _, ok := StrategyActionMap[strategy]; !ok {
+			return fmt.Errorf("unknown strategy %d", strategy)
+		}
+		g.Strategy = strategy
+		return nil
+	}
+}
+
+// CorrectAction is what the game's strategy plays with the hand.  When
+// the strategy wants an action the player cannot take, the allowed
+// action worth the most is correct instead
+func (g *Game) CorrectAction(player *Player, index int, upcard cards.Card) Action {
+
+	action := StrategyActionMap[g.Strategy](io.Discard, nil, player, upcard, index, g.CardCounter, StageDeciding)
+	allowed := player.AllowedActions(index)
+	if isAllowed(action, allowed) {
+		return action
+	}
+
+	hand := player.Hands[index]
+	best := ActionStand
+	bestEv := ActionEv(hand, upcard, ActionStand)
+	for _, a := range allowed {
+		ev := ActionEv(hand, upcard, a)
+		if ev > bestEv {
+			best, bestEv = a, ev
+		}
+	}
+
+	return best
+}
+
+// TrackDecisions checks every decision a human player makes on a hand
+// against the strategy, adding it to the session and lifetime accuracy.
+// The strategies are classic charts, so other variants are not tracked
+func (g *Game) TrackDecisions(e Event) {
+
+	if g.Variant != VariantClassic {
+		return
+	}
+
+	if e.Type != EventActionTaken || e.Stage != StageDeciding || e.HandId == 0 || e.Action == ActionQuit {
+		return
+	}
+
+	player := g.findPlayer(e.Player, e.Seat)
+	if player == nil || player.Type != PlayerTypeHuman {
+		return
+	}
+
+	index := -1
+	for i, hand := range player.Hands {
+		if hand.Id == e.HandId {
+			index = i
+		}
+	}
+	dealerHand := g.Dealer.Hands[0]
+	if index < 0 || len(player.Hands[index].Cards) < 2 || len(dealerHand.Cards) < 2 {
+		return
+	}
+
+	hand := player.Hands[index]
+	upcard := dealerHand.Cards[1]
+	correct := g.CorrectAction(player, index, upcard)
+
+	cost := 0.0
+	if e.Action != correct {
+		cost = ActionEv(hand, upcard, correct) - ActionEv(hand, upcard, e.Action)
+		if cost < 0 {
+			cost = 0
+		}
+	}
+
+	category := hand.Category()
+	if g.Accuracy == nil {
+		g.Accuracy = map[string]*Accuracy{}
+	}
+	session, ok := g.Accuracy[player.Name]
+	if !ok {
+		session = &Accuracy{}
+		g.Accuracy[player.Name] = session
+	}
+
+	session.Add(category, e.Action == correct, cost)
+	if e.Action != correct {
+		session.Mistakes = append(session.Mistakes, Mistake{
+			Round:    e.Round,
+			Player:   player.Name,
+			Cell:     hand.Cell(upcard),
+			Category: category,
+			Action:   e.Action,
+			Correct:  correct,
+			Cost:     cost,
+		})
+	}
+
+	if player.Profile != nil {
+		player.Profile.Accuracy.Add(category, e.Action == correct, cost)
+	}
+}
+
+// RenderAccuracy is the end of session report of every human player's
+// decisions
+func (g *Game) RenderAccuracy(output io.Writer) {
+
+	names := []string{}
+	for name := range g.Accuracy {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fmt.Fprintf(output, "************** %s Decision Report (%s strategy) **************\n", name, g.Strategy)
+		g.Accuracy[name].Report(output)
+		if g.Profiles != nil {
+			if profile, ok := g.Profiles.Profiles[name]; ok {
+				fmt.Fprintln(output, "Lifetime: "+profile.Accuracy.Summary())
+			}
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package blackjack_test
+
+import (
+	"blackjack"
+	"io"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/mbarley333/cards"
+)
+
+func TestTrackDecisions(t *testing.T) {
+	t.Parallel()
+
+	stack := []cards.Card{
+		{Rank: cards.Ten, Suit: cards.Club},
+		{Rank: cards.Seven, Suit: cards.Club},
+		{Rank: cards.Six, Suit: cards.Club},
+		{Rank: cards.King, Suit: cards.Spade},
+		{Rank: cards.Two, Suit: cards.Heart},
+	}
+
+	g, err := blackjack.NewBlackjackGame(
+		blackjack.WithCustomDeck(cards.Deck{Cards: stack}),
+		blackjack.WithIncomingDeck(false),
+		blackjack.WithOutput(io.Discard),
+		blackjack.WithRendering(false),
+		blackjack.WithStrategy(blackjack.StrategyBasic),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := &blackjack.Player{
+		Name: "Ravage",
+		Type: blackjack.PlayerTypeHuman,
+		Cash: 100,
+	}
+	g.AddPlayer(p)
+
+	g.StartRound()
+	g.PlaceBet(p, 10)
+	g.ApplyAction(p, blackjack.ActionStand)
+
+	want := &blackjack.Accuracy{
+		Decisions: 1,
+		Categories: map[string]*blackjack.CategoryAccuracy{
+			"hard": {Decisions: 1},
+		},
+		Mistakes: []blackjack.Mistake{
+			{
+				Round:    1,
+				Player:   "Ravage",
+				Cell:     "hard 16 v 10",
+				Category: blackjack.CategoryHard,
+				Action:   blackjack.ActionStand,
+				Correct:  blackjack.ActionHit,
+			},
+		},
+	}
+	got := g.Accuracy["Ravage"]
+
+	if !cmp.Equal(want, got, cmpopts.IgnoreFields(blackjack.Accuracy{}, "EvLost"), cmpopts.IgnoreFields(blackjack.CategoryAccuracy{}, "EvLost"), cmpopts.IgnoreFields(blackjack.Mistake{}, "Cost")) {
+		t.Fatal(cmp.Diff(want, got))
+	}
+
+	if got.Mistakes[0].Cost <= 0 {
+		t.Fatalf("want a cost for standing on 16 v 10, got: %f", got.Mistakes[0].Cost)
+	}
+}
+
+func TestTrackDecisionsClassicOnly(t *testing.T) {
+	t.Parallel()
+
+	stack := []cards.Card{
+		{Rank: cards.Ten, Suit: cards.Club},
+		{Rank: cards.Seven, Suit: cards.Club},
+		{Rank: cards.Six, Suit: cards.Club},
+		{Rank: cards.King, Suit: cards.Spade},
+		{Rank: cards.Two, Suit: cards.Heart},
+	}
+
+	g, err := blackjack.NewBlackjackGame(
+		blackjack.WithCustomDeck(cards.Deck{Cards: stack}),
+		blackjack.WithIncomingDeck(false),
+		blackjack.WithOutput(io.Discard),
+		blackjack.WithRendering(false),
+		blackjack.WithVariant(blackjack.VariantFreeBet),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := &blackjack.Player{
+		Name: "Ravage",
+		Type: blackjack.PlayerTypeHuman,
+		Cash: 100,
+	}
+	g.AddPlayer(p)
+
+	g.StartRound()
+	g.PlaceBet(p, 10)
+	g.ApplyAction(p, blackjack.ActionStand)
+
+	got := g.Accuracy["Ravage"]
+	if got != nil && got.Decisions != 0 {
+		t.Fatalf("want no decisions tracked under free bet, got: %d", got.Decisions)
+	}
+}
+
+func TestActionEv(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		description string
+		hand        *blackjack.Hand
+		upcard      cards.Card
+		better      blackjack.Action
+		worse       blackjack.Action
+	}
+
+	tcs := []testCase{
+		{
+			description: "hit hard 16 v 10",
+			hand:        &blackjack.Hand{Cards: []cards.Card{{Rank: cards.Ten}, {Rank: cards.Six}}},
+			upcard:      cards.Card{Rank: cards.Ten},
+			better:      blackjack.ActionHit,
+			worse:       blackjack.ActionStand,
+		},
+		{
+			description: "double 11 v 6",
+			hand:        &blackjack.Hand{Cards: []cards.Card{{Rank: cards.Five}, {Rank: cards.Six}}},
+			upcard:      cards.Card{Rank: cards.Six},
+			better:      blackjack.ActionDoubleDown,
+			worse:       blackjack.ActionHit,
+		},
+		{
+			description: "split eights v 9",
+			hand:        &blackjack.Hand{Cards: []cards.Card{{Rank: cards.Eight}, {Rank: cards.Eight}}},
+			upcard:      cards.Card{Rank: cards.Nine},
+			better:      blackjack.ActionSplit,
+			worse:       blackjack.ActionHit,
+		},
+		{
+			description: "stand soft 19 v 5",
+			hand:        &blackjack.Hand{Cards: []cards.Card{{Rank: cards.Ace}, {Rank: cards.Eight}}},
+			upcard:      cards.Card{Rank: cards.Five},
+			better:      blackjack.ActionStand,
+			worse:       blackjack.ActionHit,
+		},
+	}
+
+	for _, tc := range tcs {
+		better := blackjack.ActionEv(tc.hand, tc.upcard, tc.better)
+		worse := blackjack.ActionEv(tc.hand, tc.upcard, tc.worse)
+		if better <= worse {
+			t.Fatalf("%s: want %s (%f) worth more than %s (%f)", tc.description, tc.better, better, tc.worse, worse)
+		}
+	}
+}
+
+func TestAiActionCount(t *testing.T) {
+	t.Parallel()
+
+	p := &blackjack.Player{
+		Cash: 100,
+		Hands: []*blackjack.Hand{
+			{Id: 1, Bet: 10, Cards: []cards.Card{{Rank: cards.Ten}, {Rank: cards.Six}}},
+		},
+	}
+	upcard := cards.Card{Rank: cards.Ten}
+
+	type testCase struct {
+		trueCount float64
+		want      blackjack.Action
+	}
+
+	tcs := []testCase{
+		{trueCount: -1, want: blackjack.ActionHit},
+		{trueCount: 0, want: blackjack.ActionStand},
+		{trueCount: 3, want: blackjack.ActionStand},
+	}
+
+	for _, tc := range tcs {
+		c := blackjack.CardCounter{TrueCount: tc.trueCount}
+		got := blackjack.AiActionCount(io.Discard, nil, p, upcard, 0, c, blackjack.StageDeciding)
+		if tc.want != got {
+			t.Fatalf("true count %.1f want: %s, got: %s", tc.trueCount, tc.want, got)
+		}
+	}
+}
@@ -12,42 +12,36 @@ func AiActionBasic(output io.Writer, input io.Reader, player *Player, dealerCard
 	handValue := player.Hands[index].Score()
 	dealerCardValue := ScoreDealerHoleCard(dealerCard)
 
-	isSoft := false
 	isSplitable := false
+	isSoft := handValue != player.Hands[index].MinScore()
+	canDouble := len(player.Hands[index].Cards) == 2 && player.Cash > player.Hands[index].Bet
+	pair := player.Hands[index].Cards[0].Rank
 
-	if player.Hands[index].Cards[0].Rank == player.Hands[index].Cards[1].Rank {
+	if len(player.Hands[index].Cards) == 2 && player.Hands[index].Cards[0].Rank == player.Hands[index].Cards[1].Rank {
 		isSplitable = true
 	}
 
-	for _, card := range player.Hands[index].Cards {
-		length := len(player.Hands[index].Cards)
-		if card.Rank == cards.Ace && length == 2 {
-			isSoft = true
-		}
-	}
-
 	// split aces and eights
-	if isSplitable && (player.Hands[index].Cards[0].Rank == cards.Ace || player.Hands[index].Cards[0].Rank == cards.Eight) {
+	if isSplitable && (pair == cards.Ace || pair == cards.Eight) {
 		action = ActionSplit
 		// split all pairs when dealer showing 6 or less AND pair != 4,5,10
-	} else if isSplitable && (player.Hands[index].Cards[0].Rank != cards.Five && player.Hands[index].Cards[0].Rank != cards.Four && player.Hands[index].Cards[0].Rank <= 9 && dealerCardValue <= 6) {
+	} else if isSplitable && (pair != cards.Five && pair != cards.Four && pair <= 9 && dealerCardValue <= 6) {
+		action = ActionSplit
+		// with double after split, fours are split against 5 and 6
+	} else if isSplitable && pair == cards.Four && (dealerCardValue == 5 || dealerCardValue == 6) {
+		action = ActionSplit
+	} else if isSplitable && (pair == cards.Two || pair == cards.Three || pair == cards.Seven) && dealerCardValue == 7 {
 		action = ActionSplit
-	} else if (handValue == 10 && dealerCardValue < handValue || handValue == 11 && dealerCardValue < handValue) && player.Cash > player.Hands[index].Bet {
+	} else if isSplitable && pair == cards.Nine && (dealerCardValue == 8 || dealerCardValue == 9) {
+		action = ActionSplit
+	} else if (handValue == 10 && dealerCardValue < handValue || handValue == 11) && canDouble {
 		action = ActionDoubleDown
-	} else if handValue == 9 && dealerCardValue >= 3 && dealerCardValue <= 6 && player.Cash > player.Hands[index].Bet {
+	} else if handValue == 9 && dealerCardValue >= 3 && dealerCardValue <= 6 && canDouble {
 		action = ActionDoubleDown
 	} else if handValue <= 11 {
 		action = ActionHit
-	} else if handValue <= 15 && isSoft {
-		action = ActionHit
-	} else if handValue >= 19 && isSoft {
-		action = ActionStand
-	} else if handValue >= 16 && handValue <= 18 && isSoft && dealerCardValue >= 7 {
-		action = ActionHit
-	} else if handValue >= 16 && handValue <= 18 && isSoft && dealerCardValue <= 6 && player.Cash > player.Hands[index].Bet {
-		action = ActionDoubleDown
-	} else if handValue >= 16 && handValue <= 18 && isSoft && dealerCardValue <= 6 && player.Cash < player.Hands[index].Bet {
-		action = ActionHit
+	} else if isSoft {
+		action = softAction(handValue, dealerCardValue, canDouble)
 	} else if handValue >= 17 && handValue <= 21 {
 		action = ActionStand
 	} else if handValue == 12 && dealerCardValue <= 3 {
@@ -64,6 +58,193 @@ func AiActionBasic(output io.Writer, input io.Reader, player *Player, dealerCard
 
 }
 
+// softAction is the multi-deck chart for a soft total, with the dealer
+// hitting soft 17
+func softAction(total int, dealerValue int, canDouble bool) Action {
+
+	// the lowest up card each soft total doubles against, up to a six
+	doubleFrom := map[int]int{13: 5, 14: 5, 15: 4, 16: 4, 17: 3, 18: 2, 19: 6}
+
+	if from, ok := doubleFrom[total]; ok && dealerValue >= from && dealerValue <= 6 {
+		if canDouble {
+			return ActionDoubleDown
+		}
+		if total >= 18 {
+			return ActionStand
+		}
+		return ActionHit
+	}
+
+	if total >= 19 || total == 18 && dealerValue <= 8 {
+		return ActionStand
+	}
+
+	return ActionHit
+}
+
+// Deviation is a count based change to basic strategy.  The action is
+// taken when the true count is at or above Index, or below it when
+// Above is false
+type Deviation struct {
+	Total  int
+	Pair   bool
+	Dealer int
+	Index  float64
+	Above  bool
+	Action Action
+}
+
+// Deviations are the Illustrious 18 plays, less insurance, for hard
+// totals and a pair of tens
+var Deviations = []Deviation{
+	{Total: 16, Dealer: 10, Index: 0, Above: true, Action: ActionStand},
+	{Total: 15, Dealer: 10, Index: 4, Above: true, Action: ActionStand},
+	{Total: 20, Pair: true, Dealer: 5, Index: 5, Above: true, Action: ActionSplit},
+	{Total: 20, Pair: true, Dealer: 6, Index: 4, Above: true, Action: ActionSplit},
+	{Total: 10, Dealer: 10, Index: 4, Above: true, Action: ActionDoubleDown},
+	{Total: 12, Dealer: 3, Index: 2, Above: true, Action: ActionStand},
+	{Total: 12, Dealer: 2, Index: 3, Above: true, Action: ActionStand},
+	{Total: 11, Dealer: 11, Index: 1, Above: true, Action: ActionDoubleDown},
+	{Total: 9, Dealer: 2, Index: 1, Above: true, Action: ActionDoubleDown},
+	{Total: 10, Dealer: 11, Index: 4, Above: true, Action: ActionDoubleDown},
+	{Total: 9, Dealer: 7, Index: 3, Above: true, Action: ActionDoubleDown},
+	{Total: 16, Dealer: 9, Index: 5, Above: true, Action: ActionStand},
+	{Total: 13, Dealer: 2, Index: -1, Above: false, Action: ActionHit},
+	{Total: 12, Dealer: 4, Index: 0, Above: false, Action: ActionHit},
+	{Total: 12, Dealer: 5, Index: -2, Above: false, Action: ActionHit},
+	{Total: 12, Dealer: 6, Index: -1, Above: false, Action: ActionHit},
+	{Total: 13, Dealer: 3, Index: -2, Above: false, Action: ActionHit},
+}
+
+// AiActionCount plays basic strategy adjusted by the true count
+func AiActionCount(output io.Writer, input io.Reader, player *Player, dealerCard cards.Card, index int, c CardCounter, stage Stage) Action {
+
+	hand := player.Hands[index]
+	total := hand.Score()
+	dealerValue := ScoreDealerHoleCard(dealerCard)
+	isPair := len(hand.Cards) == 2 && min(int(hand.Cards[0].Rank), 10) == min(int(hand.Cards[1].Rank), 10)
+	isSoft := total != hand.MinScore()
+
+	for _, d := range Deviations {
+		if d.Total != total || d.Dealer != dealerValue || d.Pair != isPair || isSoft {
+			continue
+		}
+		if d.Above && c.TrueCount < d.Index || !d.Above && c.TrueCount >= d.Index {
+			continue
+		}
+		if d.Action == ActionDoubleDown && !player.CanDouble(index) || d.Action == ActionSplit && !player.CanSplit(index) {
+			continue
+		}
+		return d.Action
+	}
+
+	return AiActionBasic(output, input, player, dealerCard, index, c, stage)
+}
+
+func AiActionFreeBet(output io.Writer, input io.Reader, player *Player, dealerCard cards.Card, index int, c CardCounter, stage Stage) Action {
+
+	var action Action
+
+	// always take the casino's money. split free pairs except fives
+	// which are better played as a free double on 10
+	if player.IsFreeSplit(index) && player.Hands[index].Cards[0].Rank != cards.Five {
+		action = ActionSplit
+	} else if player.IsFreeDouble(index) {
+		action = ActionDoubleDown
+	} else {
+		action = AiActionBasic(output, input, player, dealerCard, index, c, stage)
+	}
+
+	return action
+}
+
+func AiActionDoubleExposure(output io.Writer, input io.Reader, player *Player, dealerCard cards.Card, index int, c CardCounter, stage Stage) Action {
+
+	// without the hole card there is nothing extra to go on
+	if player.DealerHand == nil || len(player.DealerHand.Cards) < 2 {
+		return AiActionBasic(output, input, player, dealerCard, index, c, stage)
+	}
+
+	var action Action
+	hand := player.Hands[index]
+	handValue := hand.Score()
+	dealerValue := player.DealerHand.Score()
+	dealerIsSoft := dealerValue != player.DealerHand.MinScore()
+	canDouble := player.CanDouble(index)
+	canSplit := player.CanSplit(index)
+	isSoft := handValue != hand.MinScore()
+
+	if player.DealerHand.IsBlackjack() || handValue == 21 {
+		action = ActionStand
+	} else if dealerValue >= 18 || dealerValue == 17 && !dealerIsSoft {
+		// dealer is pat and wins ties so we must beat the total
+		if handValue > dealerValue {
+			action = ActionStand
+		} else {
+			action = ActionHit
+		}
+	} else if dealerValue >= 12 && !dealerIsSoft {
+		// dealer is stiff and must draw
+		if canSplit && hand.Cards[0].Rank != cards.Five && hand.Cards[0].Rank < cards.Ten {
+			action = ActionSplit
+		} else if canDouble && (handValue >= 9 && handValue <= 11 || isSoft && handValue <= 19) {
+			action = ActionDoubleDown
+		} else if handValue >= 12 {
+			action = ActionStand
+		} else {
+			action = ActionHit
+		}
+	} else {
+		// dealer total is low so play it like an up card of the same value
+		upcard := cards.Card{Rank: cards.Seven}
+		if dealerValue <= 10 {
+			upcard.Rank = cards.Rank(dealerValue)
+		} else if dealerValue == 11 {
+			upcard.Rank = cards.Ace
+		}
+		action = AiActionBasic(output, input, player, upcard, index, c, stage)
+	}
+
+	return action
+}
+
+// handEv is the expected value of the hand at index played as well as
+// the player can against the dealer's upcard
+func handEv(p *Player, index int, upcard cards.Card) float64 {
+	hand := p.Hands[index]
+	best := ActionEv(hand, upcard, ActionStand)
+	for _, action := range p.AllowedActions(index) {
+		ev := ActionEv(hand, upcard, action)
+		if ev > best {
+			best = ev
+		}
+	}
+	return best
+}
+
+// AiSwitch switches the second cards of the spot's hands when the
+// switched hands are worth more against the dealer's upcard than the
+// hands as dealt
+func AiSwitch(g *Game) error {
+
+	p := g.ActivePlayer
+	index := p.HandIndex
+	upcard := g.Dealer.Hands[0].Cards[1]
+	keep := handEv(p, index, upcard) + handEv(p, index+1, upcard)
+
+	p.SwitchCards(index)
+	switched := handEv(p, index, upcard) + handEv(p, index+1, upcard)
+	p.SwitchCards(index)
+
+	if switched > keep {
+		p.Action = ActionSwitch
+	} else {
+		p.Action = ActionKeep
+	}
+
+	return nil
+}
+
 func AiActionStandOnly(output io.Writer, input io.Reader, player *Player, dealerCard cards.Card, index int, c CardCounter, stage Stage) Action {
 
 	return ActionStand
@@ -71,12 +252,18 @@ func AiActionStandOnly(output io.Writer, input io.Reader, player *Player, dealer
 
 func AiBet(g *Game) error {
 
-	if g.ActivePlayer.Record.HandsPlayed == g.ActivePlayer.AiRoundsToPlay {
+	// rounds, not hands, as switch, spots and splits play several hands a round
+	if g.ActivePlayer.RoundsPlayed >= g.ActivePlayer.AiRoundsToPlay {
 		g.ActivePlayer.Action = ActionQuit
 	} else {
-		bet := 1
-		g.ActivePlayer.Cash -= bet
-		g.ActivePlayer.Hands[g.ActivePlayer.HandIndex].Bet += bet
+		bet := g.ActivePlayer.MinBet()
+		for _, index := range g.ActivePlayer.SpotIndexes() {
+			g.ActivePlayer.HandIndex = index
+			if g.ActivePlayer.MaxBet() >= bet {
+				g.ActivePlayer.PlaceBet(index, bet)
+			}
+		}
+		g.ActivePlayer.HandIndex = 0
 
 	}
 	return nil
@@ -3,9 +3,11 @@ package blackjack_test
 import (
 	"blackjack"
 	"bytes"
+	"io"
 	"strings"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/mbarley333/cards"
 )
 
@@ -194,3 +196,351 @@ func TestAiBasicAction(t *testing.T) {
 	}
 
 }
+
+func TestAiBasicSoftHands(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		second      cards.Rank
+		upcard      cards.Rank
+		cash        int
+		want        blackjack.Action
+		description string
+	}
+
+	tcs := []testCase{
+		{second: cards.Seven, upcard: cards.Seven, cash: 100, want: blackjack.ActionStand, description: "Soft 18 v 7 stands"},
+		{second: cards.Seven, upcard: cards.Eight, cash: 100, want: blackjack.ActionStand, description: "Soft 18 v 8 stands"},
+		{second: cards.Seven, upcard: cards.Nine, cash: 100, want: blackjack.ActionHit, description: "Soft 18 v 9 hits"},
+		{second: cards.Seven, upcard: cards.Two, cash: 100, want: blackjack.ActionDoubleDown, description: "Soft 18 v 2 doubles"},
+		{second: cards.Seven, upcard: cards.Two, cash: 0, want: blackjack.ActionStand, description: "Soft 18 v 2 stands without cash to double"},
+		{second: cards.Six, upcard: cards.Two, cash: 100, want: blackjack.ActionHit, description: "Soft 17 v 2 hits"},
+		{second: cards.Two, upcard: cards.Four, cash: 100, want: blackjack.ActionHit, description: "Soft 13 v 4 hits"},
+		{second: cards.Two, upcard: cards.Five, cash: 100, want: blackjack.ActionDoubleDown, description: "Soft 13 v 5 doubles"},
+		{second: cards.Eight, upcard: cards.Six, cash: 100, want: blackjack.ActionDoubleDown, description: "Soft 19 v 6 doubles"},
+	}
+
+	for _, tc := range tcs {
+		p := &blackjack.Player{
+			Hands: []*blackjack.Hand{
+				{
+					Cards: []cards.Card{{Rank: cards.Ace, Suit: cards.Club}, {Rank: tc.second, Suit: cards.Heart}},
+					Bet:   10,
+				},
+			},
+			Cash: tc.cash,
+		}
+
+		got := blackjack.AiActionBasic(io.Discard, nil, p, cards.Card{Rank: tc.upcard, Suit: cards.Spade}, 0, blackjack.CardCounter{}, blackjack.StageDeciding)
+		if tc.want != got {
+			t.Errorf("%s: want %q, got %q", tc.description, tc.want, got)
+		}
+	}
+}
+
+func TestAiFreeBetAction(t *testing.T) {
+	t.Parallel()
+
+	g, err := blackjack.NewBlackjackGame(
+		blackjack.WithVariant(blackjack.VariantFreeBet),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type testCase struct {
+		playerCards []cards.Card
+		dealerCard  cards.Card
+		action      blackjack.Action
+		bet         int
+		cash        int
+		description string
+	}
+	tcs := []testCase{
+		{
+			playerCards: []cards.Card{{Rank: cards.Seven, Suit: cards.Club}, {Rank: cards.Three, Suit: cards.Club}},
+			dealerCard:  cards.Card{Rank: cards.Ace, Suit: cards.Club},
+			action:      blackjack.ActionDoubleDown,
+			bet:         10,
+			cash:        0,
+			description: "Free double on hard 10 vs Ace with no cash",
+		},
+		{
+			playerCards: []cards.Card{{Rank: cards.Two, Suit: cards.Club}, {Rank: cards.Two, Suit: cards.Heart}},
+			dealerCard:  cards.Card{Rank: cards.Ten, Suit: cards.Club},
+			action:      blackjack.ActionSplit,
+			bet:         10,
+			cash:        0,
+			description: "Free split of twos vs Ten",
+		},
+		{
+			playerCards: []cards.Card{{Rank: cards.Five, Suit: cards.Club}, {Rank: cards.Five, Suit: cards.Heart}},
+			dealerCard:  cards.Card{Rank: cards.Six, Suit: cards.Club},
+			action:      blackjack.ActionDoubleDown,
+			bet:         1,
+			cash:        10,
+			description: "Fives are doubled not split",
+		},
+		{
+			playerCards: []cards.Card{{Rank: cards.Ten, Suit: cards.Club}, {Rank: cards.Six, Suit: cards.Heart}},
+			dealerCard:  cards.Card{Rank: cards.Ten, Suit: cards.Club},
+			action:      blackjack.ActionHit,
+			bet:         1,
+			cash:        10,
+			description: "Basic strategy otherwise",
+		},
+	}
+
+	output := &bytes.Buffer{}
+	input := strings.NewReader("")
+
+	for _, tc := range tcs {
+		p := &blackjack.Player{
+			Hands: []*blackjack.Hand{
+				{
+					Cards: tc.playerCards,
+					Bet:   tc.bet,
+				},
+			},
+			Decide: blackjack.AiActionFreeBet,
+			Cash:   tc.cash,
+		}
+		g.AddPlayer(p)
+
+		want := tc.action
+
+		index := 0
+		got := p.Decide(output, input, p, tc.dealerCard, index, g.CardCounter, g.Stage)
+
+		if want != got {
+			t.Fatalf("%q: wanted: %q, got: %q", tc.description, want.String(), got.String())
+		}
+		g.Players = []*blackjack.Player{}
+	}
+}
+
+func TestAiDoubleExposureAction(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		playerCards []cards.Card
+		dealerCards []cards.Card
+		cash        int
+		action      blackjack.Action
+		description string
+	}
+	tcs := []testCase{
+		{
+			playerCards: []cards.Card{{Rank: cards.Ten, Suit: cards.Club}, {Rank: cards.Eight, Suit: cards.Club}},
+			dealerCards: []cards.Card{{Rank: cards.Ten, Suit: cards.Heart}, {Rank: cards.Nine, Suit: cards.Heart}},
+			cash:        10,
+			action:      blackjack.ActionHit,
+			description: "Hit 18 against dealer pat 19",
+		},
+		{
+			playerCards: []cards.Card{{Rank: cards.Ten, Suit: cards.Club}, {Rank: cards.Nine, Suit: cards.Club}},
+			dealerCards: []cards.Card{{Rank: cards.Ten, Suit: cards.Heart}, {Rank: cards.Eight, Suit: cards.Heart}},
+			cash:        10,
+			action:      blackjack.ActionStand,
+			description: "Stand 19 against dealer pat 18",
+		},
+		{
+			playerCards: []cards.Card{{Rank: cards.Ten, Suit: cards.Club}, {Rank: cards.Two, Suit: cards.Club}},
+			dealerCards: []cards.Card{{Rank: cards.Ten, Suit: cards.Heart}, {Rank: cards.Six, Suit: cards.Heart}},
+			cash:        10,
+			action:      blackjack.ActionStand,
+			description: "Stand 12 against dealer stiff 16",
+		},
+		{
+			playerCards: []cards.Card{{Rank: cards.Ace, Suit: cards.Club}, {Rank: cards.Seven, Suit: cards.Club}},
+			dealerCards: []cards.Card{{Rank: cards.Ten, Suit: cards.Heart}, {Rank: cards.Five, Suit: cards.Heart}},
+			cash:        10,
+			action:      blackjack.ActionDoubleDown,
+			description: "Double soft 18 against dealer stiff 15",
+		},
+		{
+			playerCards: []cards.Card{{Rank: cards.Ace, Suit: cards.Club}, {Rank: cards.Seven, Suit: cards.Club}},
+			dealerCards: []cards.Card{{Rank: cards.Ten, Suit: cards.Heart}, {Rank: cards.Five, Suit: cards.Heart}},
+			cash:        0,
+			action:      blackjack.ActionStand,
+			description: "Stand soft 18 against dealer stiff 15 without the cash to double",
+		},
+		{
+			playerCards: []cards.Card{{Rank: cards.Eight, Suit: cards.Club}, {Rank: cards.Eight, Suit: cards.Diamond}},
+			dealerCards: []cards.Card{{Rank: cards.Ten, Suit: cards.Heart}, {Rank: cards.Six, Suit: cards.Heart}},
+			cash:        10,
+			action:      blackjack.ActionSplit,
+			description: "Split eights against dealer stiff 16",
+		},
+		{
+			playerCards: []cards.Card{{Rank: cards.Eight, Suit: cards.Club}, {Rank: cards.Eight, Suit: cards.Diamond}},
+			dealerCards: []cards.Card{{Rank: cards.Ten, Suit: cards.Heart}, {Rank: cards.Six, Suit: cards.Heart}},
+			cash:        0,
+			action:      blackjack.ActionStand,
+			description: "Stand eights against dealer stiff 16 without the cash to split",
+		},
+		{
+			playerCards: []cards.Card{{Rank: cards.Ten, Suit: cards.Club}, {Rank: cards.Six, Suit: cards.Club}},
+			dealerCards: []cards.Card{{Rank: cards.Two, Suit: cards.Heart}, {Rank: cards.Eight, Suit: cards.Heart}},
+			cash:        10,
+			action:      blackjack.ActionHit,
+			description: "Hit 16 against dealer 10",
+		},
+	}
+
+	output := &bytes.Buffer{}
+	input := strings.NewReader("")
+
+	for _, tc := range tcs {
+		p := &blackjack.Player{
+			Hands: []*blackjack.Hand{
+				{
+					Cards: tc.playerCards,
+					Bet:   1,
+				},
+			},
+			Cash:       tc.cash,
+			Variant:    blackjack.VariantDoubleExposure,
+			DealerHand: &blackjack.Hand{Cards: tc.dealerCards},
+		}
+
+		want := tc.action
+
+		index := 0
+		got := blackjack.AiActionDoubleExposure(output, input, p, tc.dealerCards[1], index, blackjack.CardCounter{}, blackjack.StageDeciding)
+
+		if want != got {
+			t.Fatalf("%q: wanted: %q, got: %q", tc.description, want.String(), got.String())
+		}
+	}
+}
+
+func TestAiSwitch(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		hand1       []cards.Card
+		hand2       []cards.Card
+		upcard      cards.Card
+		action      blackjack.Action
+		description string
+	}
+	tcs := []testCase{
+		{
+			hand1:       []cards.Card{{Rank: cards.Ten, Suit: cards.Club}, {Rank: cards.Six, Suit: cards.Club}},
+			hand2:       []cards.Card{{Rank: cards.Five, Suit: cards.Heart}, {Rank: cards.Ace, Suit: cards.Heart}},
+			upcard:      cards.Card{Rank: cards.Seven, Suit: cards.Spade},
+			action:      blackjack.ActionSwitch,
+			description: "16 and soft 16 switch to 21 and 11",
+		},
+		{
+			hand1:       []cards.Card{{Rank: cards.Ten, Suit: cards.Club}, {Rank: cards.King, Suit: cards.Club}},
+			hand2:       []cards.Card{{Rank: cards.Nine, Suit: cards.Heart}, {Rank: cards.Ten, Suit: cards.Heart}},
+			upcard:      cards.Card{Rank: cards.Seven, Suit: cards.Spade},
+			action:      blackjack.ActionKeep,
+			description: "20 and 19 keep",
+		},
+		{
+			hand1:       []cards.Card{{Rank: cards.Three, Suit: cards.Club}, {Rank: cards.Seven, Suit: cards.Club}},
+			hand2:       []cards.Card{{Rank: cards.Four, Suit: cards.Heart}, {Rank: cards.Five, Suit: cards.Heart}},
+			upcard:      cards.Card{Rank: cards.Six, Suit: cards.Spade},
+			action:      blackjack.ActionKeep,
+			description: "10 and 9 keep to double both against a 6",
+		},
+		{
+			hand1:       []cards.Card{{Rank: cards.Three, Suit: cards.Club}, {Rank: cards.Seven, Suit: cards.Club}},
+			hand2:       []cards.Card{{Rank: cards.Four, Suit: cards.Heart}, {Rank: cards.Five, Suit: cards.Heart}},
+			upcard:      cards.Card{Rank: cards.Ten, Suit: cards.Spade},
+			action:      blackjack.ActionSwitch,
+			description: "10 and 9 switch to 8 and 11 against a 10",
+		},
+	}
+
+	for _, tc := range tcs {
+		g, err := blackjack.NewBlackjackGame()
+		if err != nil {
+			t.Fatal(err)
+		}
+		g.Dealer.Hands[0].Cards = []cards.Card{{Rank: cards.Two, Suit: cards.Diamond}, tc.upcard}
+
+		p := &blackjack.Player{
+			Cash: 100,
+			Hands: []*blackjack.Hand{
+				{Id: 1, Bet: 10, Cards: tc.hand1},
+				{Id: 2, Bet: 10, Cards: tc.hand2},
+			},
+		}
+		g.SetActivePlayer(p)
+
+		err = blackjack.AiSwitch(g)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := tc.action
+		got := p.Action
+
+		if want != got {
+			t.Fatalf("%q: wanted: %q, got: %q", tc.description, want.String(), got.String())
+		}
+
+		if !cmp.Equal(tc.hand1, p.Hands[0].Cards) || p.Hands[0].Switched {
+			t.Fatalf("%q: hands should be left as dealt", tc.description)
+		}
+	}
+}
+
+func TestAiBetStopsAfterRounds(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		variant     blackjack.Variant
+		spots       int
+		description string
+	}
+	tcs := []testCase{
+		{variant: blackjack.VariantClassic, spots: 1, description: "classic"},
+		{variant: blackjack.VariantSwitch, spots: 1, description: "switch"},
+		{variant: blackjack.VariantClassic, spots: 2, description: "two spots"},
+		{variant: blackjack.VariantSwitch, spots: 3, description: "switch on three spots"},
+	}
+
+	for _, tc := range tcs {
+		g, err := blackjack.NewBlackjackGame(
+			blackjack.WithVariant(tc.variant),
+			blackjack.WithSeed(1),
+			blackjack.WithRendering(false),
+			blackjack.WithOutput(&bytes.Buffer{}),
+			blackjack.WithNumberOfHumanPlayers(0),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		p := &blackjack.Player{
+			Name:           "Hound",
+			Type:           blackjack.PlayerTypeAiBasic,
+			Cash:           1000,
+			Spots:          tc.spots,
+			Bet:            blackjack.AiBet,
+			Switch:         blackjack.AiSwitch,
+			Decide:         blackjack.AiActionBasic,
+			AiRoundsToPlay: 3,
+		}
+		g.AddPlayer(p)
+
+		for round := 0; g.PlayAgain() && round < 10; round++ {
+			err = g.PlayRound()
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if g.PlayAgain() {
+			t.Fatalf("%s: want the AI to leave after 3 rounds, still playing after %d", tc.description, p.RoundsPlayed)
+		}
+		if p.RoundsPlayed != 3 {
+			t.Fatalf("%s: want 3 rounds played, got %d", tc.description, p.RoundsPlayed)
+		}
+	}
+}
@@ -0,0 +1,778 @@
+package blackjack
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// HiddenCard stands in for a card that is face down
+const HiddenCard = "??"
+
+// ActionNameMap is the name of each action in the API
+var ActionNameMap = map[Action]string{
+	ActionHit:        "hit",
+	ActionStand:      "stand",
+	ActionQuit:       "quit",
+	ActionDoubleDown: "double",
+	ActionSplit:      "split",
+	ActionBet:        "bet",
+	ActionSwitch:     "switch",
+	ActionKeep:       "keep",
+}
+
+// ParseAction reads an action by its API name or its key at the table
+func ParseAction(name string) (Action, bool) {
+
+	name = strings.ToLower(strings.TrimSpace(name))
+	for action, actionName := range ActionNameMap {
+		if actionName == name {
+			return action, true
+		}
+	}
+
+	action, ok := ActionMap[name]
+	return action, ok && action != None
+}
+
+// API serves tables over HTTP as JSON resources.  Players are driven
+// through the engine one request at a time and every table streams
+// its events over a websocket.  Joining a seat returns a token that
+// bets and actions for the player must carry
+//
+//	GET  /tables                list tables
+//	POST /tables                create a table from TableRules
+//	GET  /tables/{id}           table state
+//	POST /tables/{id}/players   join a seat with a JoinRequest, returning a JoinResponse
+//	POST /tables/{id}/rounds    start a round
+//	POST /tables/{id}/bets      bet on the pending spot with a BetRequest
+//	POST /tables/{id}/actions   act on the pending hand with an ActionRequest
+//	GET  /tables/{id}/events    websocket of EventMessage
+type API struct {
+	opts   []Option
+	mu     sync.Mutex
+	tables map[int]*Table
+	nextId int
+}
+
+// Table is a game hosted by the API.  Every request holds the lock
+// while it plays on the game
+type Table struct {
+	Id       int
+	Game     *Game
+	mu       sync.Mutex
+	watchers map[chan []byte]bool
+	tokens   map[string]string
+}
+
+// MaxDecks and MaxSeats cap the size of a table created over the API
+const (
+	MaxDecks = 8
+	MaxSeats = 7
+)
+
+// TableRules are the rules a new table is created with.  Zero values
+// take the game defaults
+type TableRules struct {
+	Variant          string `json:"variant"`
+	Decks            int    `json:"decks"`
+	Seats            int    `json:"seats"`
+	BlackjackTiePush bool   `json:"blackjackTiePush"`
+	Seed             int64  `json:"seed"`
+	MinBet           int    `json:"minBet"`
+	MaxBet           int    `json:"maxBet"`
+}
+
+type JoinRequest struct {
+	Name  string `json:"name"`
+	Seat  int    `json:"seat"`
+	Cash  int    `json:"cash"`
+	Spots int    `json:"spots"`
+}
+
+// JoinResponse is the table once the player is seated, with the token
+// the player's bets and actions must carry
+type JoinResponse struct {
+	TableState
+	Token string `json:"token"`
+}
+
+type BetRequest struct {
+	Player string `json:"player"`
+	Token  string `json:"token"`
+	Bet    int    `json:"bet"`
+}
+
+type ActionRequest struct {
+	Player string `json:"player"`
+	Token  string `json:"token"`
+	Action string `json:"action"`
+}
+
+// TableState is what a player at the table can see
+type TableState struct {
+	Id      int            `json:"id"`
+	Variant string         `json:"variant"`
+	Decks   int            `json:"decks"`
+	Seats   int            `json:"seats"`
+	Limits  BetLimits      `json:"limits"`
+	Round   int            `json:"round"`
+	Stage   string         `json:"stage"`
+	Dealer  HandState      `json:"dealer"`
+	Players []PlayerState  `json:"players"`
+	Pending *DecisionState `json:"pending,omitempty"`
+}
+
+type PlayerState struct {
+	Name    string      `json:"name"`
+	Seat    int         `json:"seat"`
+	Cash    int         `json:"cash"`
+	Waiting bool        `json:"waiting,omitempty"`
+	Record  Record      `json:"record"`
+	Hands   []HandState `json:"hands"`
+}
+
+type HandState struct {
+	Id      int      `json:"id"`
+	Cards   []string `json:"cards"`
+	Score   int      `json:"score,omitempty"`
+	Bet     int      `json:"bet,omitempty"`
+	Outcome string   `json:"outcome,omitempty"`
+	Payout  int      `json:"payout,omitempty"`
+}
+
+// DecisionState is the input the table is waiting for
+type DecisionState struct {
+	Type       string   `json:"type"`
+	Player     string   `json:"player"`
+	Seat       int      `json:"seat"`
+	HandIndex  int      `json:"handIndex"`
+	HandId     int      `json:"handId"`
+	DealerCard string   `json:"dealerCard,omitempty"`
+	Allowed    []string `json:"allowed"`
+}
+
+// EventMessage is an event as sent over the websocket.  The dealer's
+// hole card is left out until it is turned over
+type EventMessage struct {
+	Type     string `json:"type"`
+	Round    int    `json:"round"`
+	Stage    string `json:"stage"`
+	Player   string `json:"player,omitempty"`
+	Seat     int    `json:"seat,omitempty"`
+	HandId   int    `json:"handId,omitempty"`
+	Card     string `json:"card,omitempty"`
+	FaceDown bool   `json:"faceDown,omitempty"`
+	Action   string `json:"action,omitempty"`
+	Bet      int    `json:"bet,omitempty"`
+	Outcome  string `json:"outcome,omitempty"`
+	Payout   int    `json:"payout,omitempty"`
+	Cash     int    `json:"cash,omitempty"`
+}
+
+type apiError struct {
+	Error string `json:"error"`
+}
+
+// NewAPI creates an API with no tables.  The options are applied to
+// every table created, before the table's own rules
+func NewAPI(opts ...Option) *API {
+	return &API{
+		opts:   opts,
+		tables: map[int]*Table{},
+	}
+}
+
+// Options are the game options for the rules
+func (rules TableRules) Options() ([]Option, error) {
+
+	opts := []Option{}
+
+	if rules.Variant != "" {
+		variant, ok := VariantInputMap[strings.ToLower(rules.Variant)]
+		if !ok {
+			return nil, fmt.Errorf("unknown variant, %s", rules.Variant)
+		}
+		opts = append(opts, WithVariant(variant))
+	}
+	if rules.Decks < 0 || rules.Decks > MaxDecks {
+		return nil, fmt.Errorf("decks must be between 1 and %d", MaxDecks)
+	}
+	if rules.Seats < 0 || rules.Seats > MaxSeats {
+		return nil, fmt.Errorf("seats must be between 1 and %d", MaxSeats)
+	}
+	if rules.Decks > 0 {
+		opts = append(opts, WithDeckCount(rules.Decks))
+	}
+	seats := rules.Seats
+	if seats == 0 {
+		seats = MaxSeats
+	}
+	opts = append(opts,
+		WithSeatCount(seats),
+		WithBlackjackTiePush(rules.BlackjackTiePush),
+		WithBetLimits(rules.MinBet, rules.MaxBet),
+	)
+	if rules.Seed != 0 {
+		opts = append(opts, WithSeed(rules.Seed))
+	}
+
+	return opts, nil
+}
+
+// CreateTable starts a new table with the rules
+func (a *API) CreateTable(rules TableRules) (*Table, error) {
+
+	ruleOpts, err := rules.Options()
+	if err != nil {
+		return nil, err
+	}
+	opts := append(append([]Option{}, a.opts...), ruleOpts...)
+
+	t := &Table{watchers: map[chan []byte]bool{}, tokens: map[string]string{}}
+	opts = append(opts,
+		WithOutput(io.Discard),
+		WithInput(strings.NewReader("")),
+		WithRendering(false),
+		WithNumberOfHumanPlayers(0),
+		WithSubscriber(t.broadcast),
+	)
+
+	g, err := NewBlackjackGame(opts...)
+	if err != nil {
+		return nil, err
+	}
+	t.Game = g
+
+	a.mu.Lock()
+	a.nextId++
+	t.Id = a.nextId
+	a.tables[t.Id] = t
+	a.mu.Unlock()
+
+	return t, nil
+}
+
+// Table returns the table with the id
+func (a *API) Table(id int) (*Table, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	t, ok := a.tables[id]
+	return t, ok
+}
+
+func (a *API) sortedTables() []*Table {
+
+	a.mu.Lock()
+	tables := []*Table{}
+	for _, t := range a.tables {
+		tables = append(tables, t)
+	}
+	a.mu.Unlock()
+
+	sort.Slice(tables, func(i, j int) bool {
+		return tables[i].Id < tables[j].Id
+	})
+
+	return tables
+}
+
+func (a *API) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if parts[0] != "tables" {
+		writeError(w, http.StatusNotFound, fmt.Errorf("%s not found", r.URL.Path))
+		return
+	}
+
+	if len(parts) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			states := []TableState{}
+			for _, t := range a.sortedTables() {
+				states = append(states, t.State())
+			}
+			writeJSON(w, http.StatusOK, states)
+		case http.MethodPost:
+			a.createTable(w, r)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("%s not allowed", r.Method))
+		}
+		return
+	}
+
+	id, err := strconv.Atoi(parts[1])
+	if err != nil || len(parts) > 3 {
+		writeError(w, http.StatusNotFound, fmt.Errorf("%s not found", r.URL.Path))
+		return
+	}
+	t, ok := a.Table(id)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("table %d not found", id))
+		return
+	}
+
+	resource := ""
+	if len(parts) == 3 {
+		resource = parts[2]
+	}
+
+	method := http.MethodPost
+	if resource == "" || resource == "events" {
+		method = http.MethodGet
+	}
+	if r.Method != method {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("%s not allowed", r.Method))
+		return
+	}
+
+	switch resource {
+	case "":
+		writeJSON(w, http.StatusOK, t.State())
+	case "players":
+		t.join(w, r)
+	case "rounds":
+		t.startRound(w)
+	case "bets":
+		t.bet(w, r)
+	case "actions":
+		t.act(w, r)
+	case "events":
+		t.stream(w, r)
+	default:
+		writeError(w, http.StatusNotFound, fmt.Errorf("%s not found", r.URL.Path))
+	}
+}
+
+func (a *API) createTable(w http.ResponseWriter, r *http.Request) {
+
+	rules := TableRules{}
+	if !readJSON(w, r, &rules) {
+		return
+	}
+
+	t, err := a.CreateTable(rules)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, t.State())
+}
+
+func readJSON(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<16))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return false
+	}
+	if len(body) == 0 {
+		return true
+	}
+
+	err = json.Unmarshal(body, v)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("bad request body, %s", err))
+		return false
+	}
+
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, apiError{Error: err.Error()})
+}
+
+// inRound is true while the engine is waiting on a decision.  It must
+// be called with the lock held
+func (t *Table) inRound() bool {
+	pending := t.Game.Pending().Type
+	return pending != DecisionNone && pending != DecisionRoundOver
+}
+
+// player finds a seated player by name.  It must be called with the
+// lock held
+func (t *Table) player(name string) (*Player, bool) {
+	for _, player := range append(append([]*Player{}, t.Game.Players...), t.Game.WaitingPlayers...) {
+		if player.Name == name {
+			return player, true
+		}
+	}
+	return nil, false
+}
+
+// authorize finds the seated player the token was issued to.  It must
+// be called with the lock held
+func (t *Table) authorize(w http.ResponseWriter, name string, token string) (*Player, bool) {
+
+	player, ok := t.player(name)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("player %s not found", name))
+		return nil, false
+	}
+	if token == "" || t.tokens[name] != token {
+		writeError(w, http.StatusForbidden, fmt.Errorf("token is not valid for %s", name))
+		return nil, false
+	}
+
+	return player, true
+}
+
+func newToken() (string, error) {
+	b := make([]byte, 16)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func (t *Table) join(w http.ResponseWriter, r *http.Request) {
+
+	join := JoinRequest{}
+	if !readJSON(w, r, &join) {
+		return
+	}
+	if join.Name == "" {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("name is required"))
+		return
+	}
+	if join.Spots < 0 || join.Spots > MaxSpots {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("spots must be between 1 and %d", MaxSpots))
+		return
+	}
+	if join.Spots == 0 {
+		join.Spots = 1
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.inRound() {
+		writeError(w, http.StatusConflict, fmt.Errorf("a round is in progress, join when it is over"))
+		return
+	}
+	if _, ok := t.player(join.Name); ok {
+		writeError(w, http.StatusConflict, fmt.Errorf("%s is already at the table", join.Name))
+		return
+	}
+
+	player := HumanPlayer(join.Name, join.Spots)
+	if join.Cash > 0 {
+		player.Cash = join.Cash
+	}
+
+	token, err := newToken()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("unable to issue a token, %s", err))
+		return
+	}
+
+	if join.Seat > 0 {
+		err = t.Game.SitPlayer(player, join.Seat)
+	} else {
+		err = t.Game.AddPlayer(player)
+	}
+	if err != nil {
+		writeError(w, http.StatusConflict, err)
+		return
+	}
+	t.tokens[join.Name] = token
+
+	writeJSON(w, http.StatusCreated, JoinResponse{TableState: t.state(), Token: token})
+}
+
+func (t *Table) startRound(w http.ResponseWriter) {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.inRound() {
+		writeError(w, http.StatusConflict, fmt.Errorf("a round is already in progress"))
+		return
+	}
+	if !t.Game.PlayAgain() {
+		writeError(w, http.StatusConflict, fmt.Errorf("no players at the table"))
+		return
+	}
+
+	t.Game.StartRound()
+	writeJSON(w, http.StatusOK, t.state())
+}
+
+func (t *Table) bet(w http.ResponseWriter, r *http.Request) {
+
+	bet := BetRequest{}
+	if !readJSON(w, r, &bet) {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	player, ok := t.authorize(w, bet.Player, bet.Token)
+	if !ok {
+		return
+	}
+
+	_, err := t.Game.PlaceBet(player, bet.Bet)
+	if err != nil {
+		writeError(w, http.StatusConflict, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, t.state())
+}
+
+func (t *Table) act(w http.ResponseWriter, r *http.Request) {
+
+	request := ActionRequest{}
+	if !readJSON(w, r, &request) {
+		return
+	}
+
+	action, ok := ParseAction(request.Action)
+	if !ok {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("unknown action, %s", request.Action))
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	player, ok := t.authorize(w, request.Player, request.Token)
+	if !ok {
+		return
+	}
+
+	_, err := t.Game.ApplyAction(player, action)
+	if err != nil {
+		writeError(w, http.StatusConflict, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, t.state())
+}
+
+// State is the table as the players see it
+func (t *Table) State() TableState {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.state()
+}
+
+// holeCardHidden is true until the dealer turns the hole card over.
+// Both cards are dealt face up in Double Exposure
+func (t *Table) holeCardHidden() bool {
+	g := t.Game
+	return g.Variant != VariantDoubleExposure && g.Stage != StageDealerPlay && g.Stage != StageOutcome
+}
+
+func (t *Table) state() TableState {
+
+	g := t.Game
+	state := TableState{
+		Id:      t.Id,
+		Variant: g.Variant.String(),
+		Decks:   g.DeckCount,
+		Seats:   g.SeatCount,
+		Limits:  g.Limits,
+		Round:   g.Round,
+		Stage:   g.Stage.String(),
+		Players: []PlayerState{},
+	}
+
+	state.Dealer = handState(g.Dealer.Hands[0], t.holeCardHidden())
+
+	for _, player := range append(append([]*Player{}, g.Players...), g.WaitingPlayers...) {
+		p := PlayerState{
+			Name:    player.Name,
+			Seat:    player.Seat,
+			Cash:    player.Cash,
+			Waiting: player.Waiting,
+			Record:  player.Record,
+			Hands:   []HandState{},
+		}
+		for _, hand := range player.Hands {
+			p.Hands = append(p.Hands, handState(hand, false))
+		}
+		state.Players = append(state.Players, p)
+	}
+
+	if t.inRound() {
+		pending := g.Pending()
+		decision := &DecisionState{
+			Type:      pending.Type.String(),
+			Player:    pending.Player.Name,
+			Seat:      pending.Player.Seat,
+			HandIndex: pending.HandIndex,
+			Allowed:   []string{},
+		}
+		if pending.HandIndex < len(pending.Player.Hands) {
+			decision.HandId = pending.Player.Hands[pending.HandIndex].Id
+		}
+		if pending.DealerCard.Rank != 0 {
+			decision.DealerCard = pending.DealerCard.Notation()
+		}
+		for _, action := range pending.Allowed {
+			decision.Allowed = append(decision.Allowed, ActionNameMap[action])
+		}
+		state.Pending = decision
+	}
+
+	return state
+}
+
+func handState(hand *Hand, holeCardHidden bool) HandState {
+
+	state := HandState{
+		Id:     hand.Id,
+		Cards:  []string{},
+		Bet:    hand.Bet,
+		Payout: hand.Payout,
+	}
+
+	for i, card := range hand.Cards {
+		if i == 0 && holeCardHidden {
+			state.Cards = append(state.Cards, HiddenCard)
+			continue
+		}
+		state.Cards = append(state.Cards, card.Notation())
+	}
+
+	if len(hand.Cards) > 0 && !holeCardHidden {
+		state.Score = hand.Score()
+	}
+	if hand.Outcome != OutcomeNone {
+		state.Outcome = hand.Outcome.String()
+	}
+
+	return state
+}
+
+// NewEventMessage is the event as it is sent to watchers of the table
+func (t *Table) NewEventMessage(e Event) EventMessage {
+
+	message := EventMessage{
+		Type:     e.Type.String(),
+		Round:    e.Round,
+		Stage:    e.Stage.String(),
+		Player:   e.Player,
+		Seat:     e.Seat,
+		HandId:   e.HandId,
+		FaceDown: e.FaceDown,
+		Action:   ActionNameMap[e.Action],
+		Bet:      e.Bet,
+		Payout:   e.Payout,
+		Cash:     e.Cash,
+	}
+
+	if e.Card.Rank != 0 && !(e.FaceDown && e.Player == t.Game.Dealer.Name) {
+		message.Card = e.Card.Notation()
+	}
+	if e.Outcome != OutcomeNone {
+		message.Outcome = e.Outcome.String()
+	}
+
+	return message
+}
+
+// broadcast sends the event to every watcher.  Events are emitted
+// while a request holds the lock.  A watcher too slow to keep up is
+// dropped rather than holding up the table
+func (t *Table) broadcast(e Event) {
+
+	if len(t.watchers) == 0 {
+		return
+	}
+
+	data, err := json.Marshal(t.NewEventMessage(e))
+	if err != nil {
+		return
+	}
+
+	for watcher := range t.watchers {
+		select {
+		case watcher <- data:
+		default:
+			delete(t.watchers, watcher)
+			close(watcher)
+		}
+	}
+}
+
+func (t *Table) watch() chan []byte {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	watcher := make(chan []byte, 256)
+	t.watchers[watcher] = true
+	return watcher
+}
+
+func (t *Table) unwatch(watcher chan []byte) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.watchers[watcher] {
+		delete(t.watchers, watcher)
+		close(watcher)
+	}
+}
+
+// stream sends the table's events to a websocket until either side
+// closes it
+func (t *Table) stream(w http.ResponseWriter, r *http.Request) {
+
+	// watch before the upgrade so no event is missed once the client
+	// has its response
+	watcher := t.watch()
+	defer t.unwatch(watcher)
+
+	ws, err := UpgradeWebSocket(w, r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	defer ws.Close()
+
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			_, err := ws.ReadMessage()
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case data, ok := <-watcher:
+			if !ok {
+				return
+			}
+			err := ws.WriteText(data)
+			if err != nil {
+				return
+			}
+		case <-closed:
+			return
+		}
+	}
+}
@@ -0,0 +1,271 @@
+package blackjack_test
+
+import (
+	"blackjack"
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/mbarley333/cards"
+)
+
+func newTestAPI(t *testing.T) *httptest.Server {
+
+	stack := []cards.Card{
+		{Rank: cards.Ten, Suit: cards.Club},
+		{Rank: cards.Seven, Suit: cards.Club},
+		{Rank: cards.Nine, Suit: cards.Club},
+		{Rank: cards.King, Suit: cards.Spade},
+	}
+
+	api := blackjack.NewAPI(
+		blackjack.WithCustomDeck(cards.Deck{Cards: stack}),
+		blackjack.WithIncomingDeck(false),
+	)
+
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func post(t *testing.T, url string, body interface{}, wantStatus int) blackjack.TableState {
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := http.Post(url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	got, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != wantStatus {
+		t.Fatalf("POST %s: want status %d, got %d: %s", url, wantStatus, resp.StatusCode, got)
+	}
+
+	state := blackjack.TableState{}
+	json.Unmarshal(got, &state)
+	return state
+}
+
+// join seats a player and returns the player's token
+func join(t *testing.T, table string, request blackjack.JoinRequest) string {
+
+	data, err := json.Marshal(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := http.Post(table+"/players", "application/json", bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	joined := blackjack.JoinResponse{}
+	json.NewDecoder(resp.Body).Decode(&joined)
+	if resp.StatusCode != http.StatusCreated || joined.Token == "" {
+		t.Fatalf("want %s seated with a token, got status %d", request.Name, resp.StatusCode)
+	}
+
+	return joined.Token
+}
+
+// openEvents does the websocket handshake by hand and returns a reader
+// of the server's frames
+func openEvents(t *testing.T, server *httptest.Server, path string) *bufio.Reader {
+
+	conn, err := net.Dial("tcp", strings.TrimPrefix(server.URL, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	conn.SetDeadline(time.Now().Add(10 * time.Second))
+
+	fmt.Fprintf(conn, "GET %s HTTP/1.1\r\nHost: test\r\nConnection: Upgrade\r\nUpgrade: websocket\r\nSec-WebSocket-Version: 13\r\nSec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n\r\n", path)
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("want status 101, got %d", resp.StatusCode)
+	}
+
+	// the accept key from the example handshake in RFC 6455
+	want := "s3pPLMBiTxaQ9kYGzzhZRbK+xOo="
+	got := resp.Header.Get("Sec-WebSocket-Accept")
+	if want != got {
+		t.Fatalf("want accept %q, got %q", want, got)
+	}
+
+	return reader
+}
+
+func readEvent(t *testing.T, reader *bufio.Reader) blackjack.EventMessage {
+
+	header := make([]byte, 2)
+	_, err := io.ReadFull(reader, header)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	length := uint64(header[1] & 0x7F)
+	switch length {
+	case 126:
+		extended := make([]byte, 2)
+		io.ReadFull(reader, extended)
+		length = uint64(binary.BigEndian.Uint16(extended))
+	case 127:
+		extended := make([]byte, 8)
+		io.ReadFull(reader, extended)
+		length = binary.BigEndian.Uint64(extended)
+	}
+
+	payload := make([]byte, length)
+	_, err = io.ReadFull(reader, payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	event := blackjack.EventMessage{}
+	err = json.Unmarshal(payload, &event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return event
+}
+
+func TestAPIPlaysRound(t *testing.T) {
+	t.Parallel()
+
+	server := newTestAPI(t)
+
+	state := post(t, server.URL+"/tables", blackjack.TableRules{Seats: 3}, http.StatusCreated)
+	table := fmt.Sprintf("%s/tables/%d", server.URL, state.Id)
+
+	token := join(t, table, blackjack.JoinRequest{Name: "Ironhide", Seat: 2, Cash: 50})
+	events := openEvents(t, server, fmt.Sprintf("/tables/%d/events", state.Id))
+
+	state = post(t, table+"/rounds", nil, http.StatusOK)
+	wantPending := &blackjack.DecisionState{Type: "Bet", Player: "Ironhide", Seat: 2, HandId: 1, Allowed: []string{"bet", "quit"}}
+	if !cmp.Equal(wantPending, state.Pending) {
+		t.Fatal(cmp.Diff(wantPending, state.Pending))
+	}
+
+	state = post(t, table+"/bets", blackjack.BetRequest{Player: "Ironhide", Token: token, Bet: 10}, http.StatusOK)
+	wantDealer := blackjack.HandState{Id: 1, Cards: []string{blackjack.HiddenCard, "K♠"}}
+	if !cmp.Equal(wantDealer, state.Dealer) {
+		t.Fatal(cmp.Diff(wantDealer, state.Dealer))
+	}
+	wantPending = &blackjack.DecisionState{Type: "Action", Player: "Ironhide", Seat: 2, HandId: 1, DealerCard: "K♠", Allowed: []string{"hit", "stand", "double"}}
+	if !cmp.Equal(wantPending, state.Pending) {
+		t.Fatal(cmp.Diff(wantPending, state.Pending))
+	}
+
+	state = post(t, table+"/actions", blackjack.ActionRequest{Player: "Ironhide", Token: token, Action: "stand"}, http.StatusOK)
+	if state.Pending != nil {
+		t.Fatalf("want no pending decision once the round is over, got %+v", state.Pending)
+	}
+
+	wantPlayer := blackjack.PlayerState{
+		Name:   "Ironhide",
+		Seat:   2,
+		Cash:   60,
+		Record: blackjack.Record{Win: 1, HandsPlayed: 1},
+		Hands:  []blackjack.HandState{{Id: 1, Cards: []string{"10♣", "9♣"}, Score: 19, Outcome: "Win", Payout: 10}},
+	}
+	if !cmp.Equal(wantPlayer, state.Players[0]) {
+		t.Fatal(cmp.Diff(wantPlayer, state.Players[0]))
+	}
+
+	hole := blackjack.EventMessage{}
+	settled := blackjack.EventMessage{}
+	for settled.Type == "" {
+		event := readEvent(t, events)
+		switch {
+		case event.Type == "CardDealt" && event.Player == "Dealer" && event.FaceDown:
+			hole = event
+		case event.Type == "HandSettled":
+			settled = event
+		}
+	}
+
+	if hole.Card != "" {
+		t.Fatalf("want the hole card left out of the event, got %q", hole.Card)
+	}
+
+	wantSettled := blackjack.EventMessage{Type: "HandSettled", Round: 1, Stage: "Outcome", Player: "Ironhide", Seat: 2, HandId: 1, Bet: 10, Outcome: "Win", Payout: 10, Cash: 60}
+	if !cmp.Equal(wantSettled, settled) {
+		t.Fatal(cmp.Diff(wantSettled, settled))
+	}
+}
+
+func TestAPIErrors(t *testing.T) {
+	t.Parallel()
+
+	server := newTestAPI(t)
+
+	post(t, server.URL+"/tables", blackjack.TableRules{Variant: "pontoon"}, http.StatusBadRequest)
+	state := post(t, server.URL+"/tables", blackjack.TableRules{}, http.StatusCreated)
+	table := fmt.Sprintf("%s/tables/%d", server.URL, state.Id)
+
+	post(t, server.URL+"/tables/99/rounds", nil, http.StatusNotFound)
+	post(t, table+"/rounds", nil, http.StatusConflict)
+
+	jazz := join(t, table, blackjack.JoinRequest{Name: "Jazz"})
+	post(t, table+"/players", blackjack.JoinRequest{Name: "Jazz"}, http.StatusConflict)
+	post(t, table+"/players", blackjack.JoinRequest{Name: "Bumblebee", Seat: 1}, http.StatusConflict)
+	bumblebee := join(t, table, blackjack.JoinRequest{Name: "Bumblebee", Seat: 2})
+
+	post(t, table+"/actions", blackjack.ActionRequest{Player: "Jazz", Token: jazz, Action: "stand"}, http.StatusConflict)
+	post(t, table+"/rounds", nil, http.StatusOK)
+	post(t, table+"/rounds", nil, http.StatusConflict)
+	post(t, table+"/players", blackjack.JoinRequest{Name: "Wheeljack"}, http.StatusConflict)
+
+	post(t, table+"/bets", blackjack.BetRequest{Player: "Bumblebee", Token: bumblebee, Bet: 10}, http.StatusConflict)
+	post(t, table+"/bets", blackjack.BetRequest{Player: "Jazz", Token: jazz, Bet: 1000}, http.StatusConflict)
+	post(t, table+"/bets", blackjack.BetRequest{Player: "Starscream", Bet: 10}, http.StatusNotFound)
+	post(t, table+"/actions", blackjack.ActionRequest{Player: "Jazz", Token: jazz, Action: "fold"}, http.StatusBadRequest)
+	post(t, table+"/actions", blackjack.ActionRequest{Player: "Jazz", Token: jazz, Action: "hit"}, http.StatusConflict)
+
+	state = post(t, table+"/actions", blackjack.ActionRequest{Player: "Jazz", Token: jazz, Action: "quit"}, http.StatusOK)
+	if state.Pending.Player != "Bumblebee" {
+		t.Fatalf("want Bumblebee to bet once Jazz quits, got %+v", state.Pending)
+	}
+}
+
+func TestAPIRequiresToken(t *testing.T) {
+	t.Parallel()
+
+	server := newTestAPI(t)
+
+	post(t, server.URL+"/tables", blackjack.TableRules{Decks: 9}, http.StatusBadRequest)
+	post(t, server.URL+"/tables", blackjack.TableRules{Seats: 8}, http.StatusBadRequest)
+	state := post(t, server.URL+"/tables", blackjack.TableRules{Decks: 8, Seats: 7}, http.StatusCreated)
+	table := fmt.Sprintf("%s/tables/%d", server.URL, state.Id)
+
+	token := join(t, table, blackjack.JoinRequest{Name: "Optimus"})
+	post(t, table+"/rounds", nil, http.StatusOK)
+
+	post(t, table+"/bets", blackjack.BetRequest{Player: "Optimus", Bet: 10}, http.StatusForbidden)
+	post(t, table+"/bets", blackjack.BetRequest{Player: "Optimus", Token: "megatron", Bet: 10}, http.StatusForbidden)
+	post(t, table+"/bets", blackjack.BetRequest{Player: "Optimus", Token: token, Bet: 10}, http.StatusOK)
+	post(t, table+"/actions", blackjack.ActionRequest{Player: "Optimus", Action: "stand"}, http.StatusForbidden)
+	post(t, table+"/actions", blackjack.ActionRequest{Player: "Optimus", Token: token, Action: "stand"}, http.StatusOK)
+}
@@ -5,6 +5,7 @@ import (
 	"io"
 	"math/rand"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -22,6 +23,8 @@ var ActionStringMap = map[Action]string{
 	ActionDoubleDown: "Double Down",
 	ActionSplit:      "Split",
 	ActionBet:        "Bet",
+	ActionSwitch:     "Switch",
+	ActionKeep:       "Keep",
 }
 
 func (a Action) String() string {
@@ -36,6 +39,8 @@ const (
 	ActionDoubleDown
 	ActionSplit
 	ActionBet
+	ActionSwitch
+	ActionKeep
 )
 
 var ActionMap = map[string]Action{
@@ -46,6 +51,8 @@ var ActionMap = map[string]Action{
 	"d": ActionDoubleDown,
 	"p": ActionSplit,
 	"b": ActionBet,
+	"w": ActionSwitch,
+	"k": ActionKeep,
 }
 
 type Outcome int
@@ -90,18 +97,38 @@ var BalanceReportMap = map[Outcome]string{
 
 type PlayerType int
 
+// A player built without a type is unknown, and is neither paced, timed
+// nor coached as a human is
 const (
-	PlayerTypeHuman PlayerType = iota
+	PlayerTypeUnknown PlayerType = iota
+	PlayerTypeHuman
 	PlayerTypeAiStandOnly
 	PlayerTypeAiBasic
 	PlayerTypeAiCustom
+	PlayerTypeAiFreeBet
+	PlayerTypeAiDoubleExposure
 )
 
+var PlayerTypeNameMap = map[PlayerType]string{
+	PlayerTypeHuman:            "Human",
+	PlayerTypeAiStandOnly:      "StandOnly",
+	PlayerTypeAiBasic:          "Basic",
+	PlayerTypeAiCustom:         "Custom",
+	PlayerTypeAiFreeBet:        "FreeBet",
+	PlayerTypeAiDoubleExposure: "DoubleExposure",
+}
+
+func (p PlayerType) String() string {
+	return PlayerTypeNameMap[p]
+}
+
 var PlayerTypeMap = map[PlayerType]func(io.Writer, io.Reader, *Player, cards.Card, int, CardCounter, Stage) Action{
-	PlayerTypeHuman:       HumanAction,
-	PlayerTypeAiStandOnly: AiActionStandOnly,
-	PlayerTypeAiBasic:     AiActionBasic,
-	PlayerTypeAiCustom:    AiActionBasic,
+	PlayerTypeHuman:            HumanAction,
+	PlayerTypeAiStandOnly:      AiActionStandOnly,
+	PlayerTypeAiBasic:          AiActionBasic,
+	PlayerTypeAiCustom:         AiActionBasic,
+	PlayerTypeAiFreeBet:        AiActionFreeBet,
+	PlayerTypeAiDoubleExposure: AiActionDoubleExposure,
 }
 
 var PlayerTypeInputMap = map[string]PlayerType{
@@ -109,13 +136,75 @@ var PlayerTypeInputMap = map[string]PlayerType{
 	"b": PlayerTypeAiBasic,
 	"s": PlayerTypeAiStandOnly,
 	"x": PlayerTypeAiBasic,
+	"f": PlayerTypeAiFreeBet,
+	"e": PlayerTypeAiDoubleExposure,
 }
 
 var PlayerTypeBetMap = map[PlayerType]func(*Game) error{
-	PlayerTypeHuman:       HumanBet,
-	PlayerTypeAiStandOnly: AiBet,
-	PlayerTypeAiBasic:     AiBet,
-	PlayerTypeAiCustom:    AiBet,
+	PlayerTypeHuman:            HumanBet,
+	PlayerTypeAiStandOnly:      AiBet,
+	PlayerTypeAiBasic:          AiBet,
+	PlayerTypeAiCustom:         AiBet,
+	PlayerTypeAiFreeBet:        AiBet,
+	PlayerTypeAiDoubleExposure: AiBet,
+}
+
+var PlayerTypeSwitchMap = map[PlayerType]func(*Game) error{
+	PlayerTypeHuman:            HumanSwitch,
+	PlayerTypeAiStandOnly:      AiSwitch,
+	PlayerTypeAiBasic:          AiSwitch,
+	PlayerTypeAiCustom:         AiSwitch,
+	PlayerTypeAiFreeBet:        AiSwitch,
+	PlayerTypeAiDoubleExposure: AiSwitch,
+}
+
+type Variant int
+
+const (
+	VariantClassic Variant = iota
+	VariantFreeBet
+	VariantDoubleExposure
+	VariantSwitch
+)
+
+var VariantMap = map[Variant]string{
+	VariantClassic:        "Classic",
+	VariantFreeBet:        "Free Bet",
+	VariantDoubleExposure: "Double Exposure",
+	VariantSwitch:         "Blackjack Switch",
+}
+
+var VariantInputMap = map[string]Variant{
+	"classic":        VariantClassic,
+	"freebet":        VariantFreeBet,
+	"doubleexposure": VariantDoubleExposure,
+	"switch":         VariantSwitch,
+}
+
+// VariantBlackjackPayoutMap is the multiple of the bet won on a blackjack
+var VariantBlackjackPayoutMap = map[Variant]int{
+	VariantClassic:        2,
+	VariantFreeBet:        2,
+	VariantDoubleExposure: 1,
+	VariantSwitch:         1,
+}
+
+// VariantDealerPush22Map is true when a dealer 22 pushes every hand still in play
+var VariantDealerPush22Map = map[Variant]bool{
+	VariantFreeBet: true,
+	VariantSwitch:  true,
+}
+
+// VariantHandCountMap is the number of hands each player starts a round with
+var VariantHandCountMap = map[Variant]int{
+	VariantClassic:        1,
+	VariantFreeBet:        1,
+	VariantDoubleExposure: 1,
+	VariantSwitch:         2,
+}
+
+func (v Variant) String() string {
+	return VariantMap[v]
 }
 
 type Stage int
@@ -127,6 +216,7 @@ const (
 	StageDeciding
 	StageDealerPlay
 	StageOutcome
+	StageSwitching
 )
 
 var StageMap = map[Stage]string{
@@ -136,6 +226,7 @@ var StageMap = map[Stage]string{
 	StageDeciding:    "Deciding",
 	StageDealerPlay:  "Dealer Play",
 	StageOutcome:     "Outcome",
+	StageSwitching:   "Switching",
 }
 
 var StageDisplayMessageMap = map[Stage]string{
@@ -145,6 +236,7 @@ var StageDisplayMessageMap = map[Stage]string{
 	StageDeciding:    "PLAYERS MAKE YOUR CHOICE",
 	StageDealerPlay:  "DEALER PLAY",
 	StageOutcome:     "OUTCOME",
+	StageSwitching:   "SWITCH OR KEEP",
 }
 
 func (s Stage) String() string {
@@ -164,6 +256,8 @@ const (
 	DialogHitOrStand
 	DialogHitSplitDoubleStand
 	DialogHitDoubleStand
+	DialogHitSplitStand
+	DialogSwitchOrKeep
 )
 
 var DialogMap = map[Dialog]string{
@@ -173,6 +267,8 @@ var DialogMap = map[Dialog]string{
 	DialogHitOrStand:          "HitOrStand",
 	DialogHitSplitDoubleStand: "HitSplitDoubleStand",
 	DialogHitDoubleStand:      "HitDoubleStand",
+	DialogHitSplitStand:       "HitSplitStand",
+	DialogSwitchOrKeep:        "SwitchOrKeep",
 }
 
 var DialogPlayerMessage = map[Dialog]string{
@@ -182,6 +278,8 @@ var DialogPlayerMessage = map[Dialog]string{
 	DialogHitOrStand:          "please choose (H)it, (S)tand or (?)Hint: ",
 	DialogHitSplitDoubleStand: "please choose (H)it, S(P)lit, (D)ouble, (S)tand or (?)Hint: ",
 	DialogHitDoubleStand:      "please choose (H)it, (D)ouble, (S)tand (?)Hint: ",
+	DialogHitSplitStand:       "please choose (H)it, S(P)lit, (S)tand or (?)Hint: ",
+	DialogSwitchOrKeep:        "enter S(W)itch or (K)eep [k]:",
 }
 
 func (d Dialog) String() string {
@@ -199,13 +297,50 @@ type Game struct {
 	IncomingDeckPosition int
 	DeckCount            int
 	random               *rand.Rand
+	source               *Source
 	CountCards           func(cards.Card, int, int, int) (int, float64)
+	CountSystem          CountSystem
 	CardCounter          CardCounter
+	Practice             *CountPractice
 	Stage                Stage
 	StageMessage         string
 	NumberHumanPlayers   int
 	NumberAiPlayers      int
 	ActivePlayer         *Player
+	Variant              Variant
+	BlackjackTiePush     bool
+	SeatCount            int
+	NoMidShoeEntry       bool
+	WaitingPlayers       []*Player
+	Round                int
+	Seed                 int64
+	ShoeId               int
+	Rendering            bool
+	Profiles             *ProfileStore
+	Strategy             Strategy
+	Accuracy             map[string]*Accuracy
+	Limits               BetLimits
+	BotCommands          []string
+	BotTimeout           time.Duration
+	BotLog               io.Writer
+	BotRounds            int
+	Bots                 []*Bot
+	DecisionTimeout      time.Duration
+	TimeBank             time.Duration
+	TimeoutPlay          TimeoutPlay
+	FullScreen           bool
+	Theme                Theme
+	Pace                 Pace
+	ShowCount            bool
+	screen               *Screen
+	subscribers          []Subscriber
+	history              *HandHistory
+	historyOutput        io.Writer
+	cursor               cursor
+	step                 step
+	pending              Decision
+	clock                *decisionClock
+	feeds                map[io.Reader]*lineFeed
 }
 
 type Option func(*Game) error
@@ -227,7 +362,7 @@ func WithOutput(output io.Writer) Option {
 
 func WithInput(input io.Reader) Option {
 	return func(g *Game) error {
-		g.input = input
+		g.input = BufferedReader(input)
 		return nil
 	}
 }
@@ -249,6 +384,18 @@ func WithDeckCount(d int) Option {
 func WithRandom(random *rand.Rand) Option {
 	return func(g *Game) error {
 		g.random = random
+		g.source = nil
+		return nil
+	}
+}
+
+// WithSeed shuffles every shoe from the seed so that a session can be
+// dealt again card for card
+func WithSeed(seed int64) Option {
+	return func(g *Game) error {
+		g.Seed = seed
+		g.source = NewSource(seed)
+		g.random = rand.New(g.source)
 		return nil
 	}
 }
@@ -268,26 +415,105 @@ func WithNumberOfAiPlayers(ai int) Option {
 	}
 }
 
+func WithVariant(variant Variant) Option {
+	return func(g *Game) error {
+		g.Variant = variant
+		return nil
+	}
+}
+
+// WithBlackjackTiePush lets tied blackjacks push in Double Exposure
+// instead of going to the dealer
+func WithBlackjackTiePush(push bool) Option {
+	return func(g *Game) error {
+		g.BlackjackTiePush = push
+		return nil
+	}
+}
+
+// WithSeatCount sets the number of seats at the table.  A table
+// without seats takes players in the order they are added
+func WithSeatCount(seats int) Option {
+	return func(g *Game) error {
+		g.SeatCount = seats
+		return nil
+	}
+}
+
+// WithNoMidShoeEntry makes players joining a shoe in progress wait
+// for the next shoe before they are dealt in
+func WithNoMidShoeEntry(noEntry bool) Option {
+	return func(g *Game) error {
+		g.NoMidShoeEntry = noEntry
+		return nil
+	}
+}
+
+// BetLimits are the table minimum and maximum bet.  A maximum of 0 is
+// no limit beyond the player's cash
+type BetLimits struct {
+	Min int `json:"min"`
+	Max int `json:"max"`
+}
+
+func WithBetLimits(min, max int) Option {
+	return func(g *Game) error {
+		if min < 0 || (max > 0 && max < min) {
+			return fmt.Errorf("bet limits $%d to $%d are not valid", min, max)
+		}
+		g.Limits = BetLimits{Min: min, Max: max}
+		return nil
+	}
+}
+
+// WithRendering turns the terminal view of the game on or off.  A game
+// without rendering can be driven headless through the engine
+func WithRendering(r bool) Option {
+	return func(g *Game) error {
+		g.Rendering = r
+		return nil
+	}
+}
+
 func NewBlackjackGame(opts ...Option) (*Game, error) {
 
+	seed := time.Now().UnixNano()
+	source := NewSource(seed)
+
 	game := &Game{
 		output:             os.Stdout,
-		input:              os.Stdin,
+		input:              BufferedReader(os.Stdin),
 		IsIncomingDeck:     true,
 		DeckCount:          6,
-		random:             rand.New(rand.NewSource(time.Now().UnixNano())),
+		Seed:               seed,
+		random:             rand.New(source),
+		source:             source,
 		CountCards:         CountHiLo,
 		NumberHumanPlayers: 1,
 		NumberAiPlayers:    0,
+		Rendering:          true,
+		Accuracy:           map[string]*Accuracy{},
 	}
 
-	deck := cards.NewDeck(
-		cards.WithNumberOfDecks(game.DeckCount),
-	)
-	game.Shoe = deck
+	game.Subscribe(game.TrackDecisions)
 
 	for _, o := range opts {
-		o(game)
+		err := o(game)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	game.Theme = ThemeFor(game.Theme, game.output)
+
+	// a custom deck replaces the shoe
+	if len(game.Shoe.Cards) == 0 {
+		game.Shoe = game.IncomingDeck()
+	}
+	game.ShoeId = 1
+
+	if game.Rendering {
+		game.Subscribe(game.RenderEvent)
 	}
 
 	if game.IsIncomingDeck {
@@ -296,7 +522,7 @@ func NewBlackjackGame(opts ...Option) (*Game, error) {
 		// how many cards must be dealt before new incoming deck
 		max := 0.17
 		min := 0.01
-		random := min + rand.Float64()*(max-min)
+		random := min + game.random.Float64()*(max-min)
 		count := len(game.Shoe.Cards)
 		fcount := float64(count)
 		val := int(fcount * random)
@@ -305,7 +531,9 @@ func NewBlackjackGame(opts ...Option) (*Game, error) {
 	}
 
 	game.Dealer = &Player{
-		Name: "Dealer",
+		Name:    "Dealer",
+		Variant: game.Variant,
+		Theme:   game.Theme,
 		Hands: []*Hand{
 			{
 				Id: 1,
@@ -313,12 +541,19 @@ func NewBlackjackGame(opts ...Option) (*Game, error) {
 		},
 	}
 
+	if game.FullScreen {
+		game.screen = NewScreen(game, game.output)
+		game.output = game.screen
+		game.input = BufferedReader(game.screen.Input(game.input))
+		game.Subscribe(game.screen.Update)
+	}
+
 	return game, nil
 }
 
 func (g *Game) PlayAgain() bool {
 	response := false
-	if len(g.Players) > 0 {
+	if len(g.Players) > 0 || len(g.WaitingPlayers) > 0 {
 		response = true
 	}
 
@@ -368,11 +603,10 @@ func (g *Game) Deal(output io.Writer) cards.Card {
 
 	if g.IsIncomingDeck {
 		if g.CardsDealt >= g.IncomingDeckPosition {
-			g.StageMessage = "NEW DECK INCOMING"
-			RenderStageMessage(g.output, g.StageMessage)
-
 			g.Shoe = g.IncomingDeck()
+			g.ShoeId++
 			g.ResetFieldsAfterIncomingDeck()
+			g.Emit(Event{Type: EventShoeShuffled})
 		}
 
 	} else {
@@ -381,19 +615,40 @@ func (g *Game) Deal(output io.Writer) cards.Card {
 	return card
 }
 
+// Penetration is how much of the shoe has been dealt
+func (g *Game) Penetration() float64 {
+	if g.DeckCount == 0 {
+		return 0
+	}
+	return float64(g.CardsDealt) / float64(g.DeckCount*52)
+}
+
 func (g *Game) ResetFieldsAfterIncomingDeck() {
 	g.CardsDealt = 0
 	g.CardCounter.Count = 0
 	g.CardCounter.TrueCount = 0
+
+	for _, player := range g.WaitingPlayers {
+		player.Waiting = false
+	}
 }
 
 func (g *Game) ResetPlayers() {
 
+	g.SeatWaitingPlayers()
+	g.Round++
+	g.Emit(Event{Type: EventRoundStarted})
+
 	for _, player := range g.Players {
 		player.HandIndex = 0
 		player.Hands = []*Hand{}
-		hand := NewHand(1)
-		player.AddHand(hand)
+		for spot := 0; spot < player.SpotCount(); spot++ {
+			for i := 0; i < VariantHandCountMap[g.Variant]; i++ {
+				hand := NewHand(player.NextHandId())
+				hand.Spot = spot
+				player.AddHand(hand)
+			}
+		}
 		player.Action = None
 		player.Message = ""
 
@@ -412,6 +667,8 @@ func (g *Game) RemoveQuitPlayers() []*Player {
 		for _, player := range g.Players {
 			if player.Action != ActionQuit {
 				newPlayers = append(newPlayers, player)
+			} else {
+				g.Emit(Event{Type: EventPlayerLeft, Player: player.Name, Seat: player.Seat, Cash: player.Cash})
 			}
 		}
 	}
@@ -420,43 +677,187 @@ func (g *Game) RemoveQuitPlayers() []*Player {
 }
 
 func (g Game) IncomingDeck() cards.Deck {
-	deck := cards.NewDeck(
+	opts := []cards.Option{
 		cards.WithNumberOfDecks(g.DeckCount),
-	)
+	}
+	if g.random != nil {
+		opts = append(opts, cards.WithRandom(g.random))
+	}
 
-	return deck
+	return cards.NewDeck(opts...)
 
 }
 
 func (g *Game) SetStage(stage Stage) {
 	g.Stage = stage
 	g.StageMessage = StageDisplayMessageMap[stage]
+	g.Emit(Event{Type: EventStageChanged})
+}
+
+// AddPlayer seats the player in the first empty seat
+func (g *Game) AddPlayer(player *Player) error {
+
+	if g.SeatCount == 0 {
+		player.Variant = g.Variant
+		player.Theme = g.Theme
+		player.Limits = g.Limits
+		player.TimeBank = g.TimeBank
+		g.Players = append(g.Players, player)
+		g.Emit(Event{Type: EventPlayerSeated, Player: player.Name, Cash: player.Cash})
+		return nil
+	}
+
+	seats := g.EmptySeats()
+	if len(seats) == 0 {
+		return fmt.Errorf("table is full, no seat for %s", player.Name)
+	}
+
+	return g.SitPlayer(player, seats[0])
 }
 
-func (g *Game) AddPlayer(player *Player) {
+// SitPlayer seats the player in the chosen seat.  Seat 1 is first
+// base and is dealt first
+func (g *Game) SitPlayer(player *Player, seat int) error {
+
+	if seat < 1 || seat > g.SeatCount {
+		return fmt.Errorf("seat %d does not exist, choose 1 to %d", seat, g.SeatCount)
+	}
+
+	if g.SeatedPlayer(seat) != nil {
+		return fmt.Errorf("seat %d is taken", seat)
+	}
+
+	player.Variant = g.Variant
+	player.Theme = g.Theme
+	player.Limits = g.Limits
+	player.TimeBank = g.TimeBank
+	player.Seat = seat
+	g.Emit(Event{Type: EventPlayerSeated, Player: player.Name, Seat: seat, Cash: player.Cash})
+
+	if g.NoMidShoeEntry && g.CardsDealt > 0 {
+		player.Waiting = true
+		g.WaitingPlayers = append(g.WaitingPlayers, player)
+		return nil
+	}
+
 	g.Players = append(g.Players, player)
+	SortBySeat(g.Players)
+
+	return nil
+}
+
+// SeatedPlayer returns the player in the seat, playing or waiting
+func (g Game) SeatedPlayer(seat int) *Player {
+	for _, player := range g.Players {
+		if player.Seat == seat {
+			return player
+		}
+	}
+	for _, player := range g.WaitingPlayers {
+		if player.Seat == seat {
+			return player
+		}
+	}
+	return nil
+}
+
+func (g Game) EmptySeats() []int {
+	seats := []int{}
+	for seat := 1; seat <= g.SeatCount; seat++ {
+		if g.SeatedPlayer(seat) == nil {
+			seats = append(seats, seat)
+		}
+	}
+	return seats
+}
+
+// SeatWaitingPlayers deals in waiting players once a new shoe has
+// started or nobody else is playing
+func (g *Game) SeatWaitingPlayers() {
+
+	waiting := []*Player{}
+	for _, player := range g.WaitingPlayers {
+		if player.Waiting && len(g.Players) > 0 {
+			waiting = append(waiting, player)
+		} else {
+			player.Waiting = false
+			g.Players = append(g.Players, player)
+		}
+	}
+	g.WaitingPlayers = waiting
+	SortBySeat(g.Players)
+}
+
+// SortBySeat puts players in dealing order from first base to third base
+func SortBySeat(players []*Player) {
+	sort.SliceStable(players, func(i, j int) bool {
+		return players[i].Seat < players[j].Seat
+	})
 }
 
 type Player struct {
 	Name           string
+	Type           PlayerType
 	Action         Action
 	Bet            func(*Game) error
+	Switch         func(*Game) error
 	Decide         func(io.Writer, io.Reader, *Player, cards.Card, int, CardCounter, Stage) Action
 	AiRoundsToPlay int
+	RoundsPlayed   int
 	Record         Record
 	Cash           int
 	Hands          []*Hand
 	HandIndex      int
+	Spots          int
+	SpotRecords    []Record
+	Seat           int
+	Waiting        bool
+	SittingOut     bool
 	Message        string
 	Dialog         Dialog
 	CurrentBet     int
+	Variant        Variant
+	DealerHand     *Hand
+	Profile        *Profile
+	Output         io.Writer
+	Input          io.Reader
+	Limits         BetLimits
+	TimeBank       time.Duration
+	Theme          Theme
+}
+
+// PlayerOutput is where questions for the player are written, the
+// player's own connection when it has one or the table otherwise
+func (g *Game) PlayerOutput(player *Player) io.Writer {
+	if player.Output != nil {
+		return player.Output
+	}
+	return g.output
+}
+
+// PlayerInput is where the player's answers are read from.  While the
+// player's decision is timed the answers are read through its clock
+func (g *Game) PlayerInput(player *Player) io.Reader {
+	if g.clock != nil && g.clock.decision.Player == player {
+		return g.clock
+	}
+	input := g.input
+	if player.Input != nil {
+		input = player.Input
+	}
+	if g.DecisionTimeout > 0 {
+		return g.contextInput(input)
+	}
+	return input
 }
 
 func (p *Player) Payout() {
 
 	for _, hand := range p.Hands {
+		// free bets are paid when the hand wins but are never
+		// returned to the player
 		if hand.Outcome == OutcomeWin {
-			hand.Payout = hand.Bet
+			hand.Payout = hand.Bet + hand.FreeBet
 			p.Cash += hand.Bet + hand.Payout
 			hand.Bet = 0
 		} else if hand.Outcome == OutcomeLose || hand.Outcome == OutcomeBust {
@@ -467,26 +868,33 @@ func (p *Player) Payout() {
 			p.Cash += hand.Bet
 			hand.Bet = 0
 		} else if hand.Outcome == OutcomeBlackjack {
-			hand.Payout = 2 * hand.Bet
+			hand.Payout = VariantBlackjackPayoutMap[p.Variant] * hand.Bet
 			p.Cash += hand.Bet + hand.Payout
 			hand.Bet = 0
 		}
+		hand.FreeBet = 0
 	}
 }
 
+// Broke quits a player who cannot cover the table minimum
 func (p *Player) Broke() {
-	if p.Cash == 0 {
+	if p.Cash < p.MinBet() {
 		p.Action = ActionQuit
 	}
 
 }
 
-func (p *Player) Split(output io.Writer, card1, card2 cards.Card, index int) {
+func (p *Player) Split(output io.Writer, card1, card2 cards.Card, index int, free bool) {
 
 	id := p.NextHandId()
 	hand := NewHand(id)
-	p.AddHand(hand)
-	indexNewHand := len(p.Hands) - 1
+	hand.Spot = p.Hands[index].Spot
+
+	// new hand is played straight after the hand it was split from
+	indexNewHand := index + 1
+	p.Hands = append(p.Hands, nil)
+	copy(p.Hands[indexNewHand+1:], p.Hands[indexNewHand:])
+	p.Hands[indexNewHand] = hand
 
 	// take last card in original hand and append to the new split hand
 	card := p.Hands[index].Cards[1]
@@ -495,9 +903,14 @@ func (p *Player) Split(output io.Writer, card1, card2 cards.Card, index int) {
 	// reset slice on original hand to only have the first card
 	p.Hands[index].Cards = p.Hands[index].Cards[:len(p.Hands[index].Cards)-1]
 
-	// mirror bet on new hand
-	p.Hands[indexNewHand].Bet += p.Hands[index].Bet
-	p.Cash -= p.Hands[index].Bet
+	// mirror bet on new hand.  a free split is funded by the casino
+	wager := p.Hands[index].Bet + p.Hands[index].FreeBet
+	if free {
+		p.Hands[indexNewHand].FreeBet += wager
+	} else {
+		p.Hands[indexNewHand].Bet += wager
+		p.Cash -= wager
+	}
 
 	// add cards to each hand
 	p.Hands[index].Cards = append(p.Hands[index].Cards, card1)
@@ -506,12 +919,6 @@ func (p *Player) Split(output io.Writer, card1, card2 cards.Card, index int) {
 
 	p.Hands[indexNewHand].Action = None
 
-	p.Message = p.Hands[index].HandStringMulti(p.Name)
-	RenderPlayerMessage(output, p)
-
-	p.Message = p.Hands[indexNewHand].HandStringMulti(p.Name)
-	RenderPlayerMessage(output, p)
-
 }
 
 func (p *Player) AddHand(hand *Hand) {
@@ -528,14 +935,14 @@ func NewHand(id int) *Hand {
 func (p *Player) SetWinLoseTie() {
 
 	for _, hand := range p.Hands {
-		if hand.Outcome == OutcomeWin || hand.Outcome == OutcomeBlackjack {
-			p.Record.Win += 1
-		} else if hand.Outcome == OutcomeTie {
-			p.Record.Tie += 1
-		} else {
-			p.Record.Lose += 1
+		p.Record.Add(hand.Outcome)
+
+		if p.SpotCount() > 1 {
+			for len(p.SpotRecords) <= hand.Spot {
+				p.SpotRecords = append(p.SpotRecords, Record{})
+			}
+			p.SpotRecords[hand.Spot].Add(hand.Outcome)
 		}
-		p.Record.HandsPlayed += 1
 	}
 }
 
@@ -552,6 +959,7 @@ func (p *Player) OutcomeReport(output io.Writer) {
 
 		str := []string{
 			p.Name,
+			p.SpotLabel(hand),
 			BalanceReportMap[hand.Outcome],
 			payout,
 			".  Cash available: $",
@@ -564,7 +972,7 @@ func (p *Player) OutcomeReport(output io.Writer) {
 
 func (p Player) DealerString() string {
 
-	return "Dealer has: [" + p.Hands[0].Cards[0].String() + "]" + "[???]"
+	return "Dealer has: " + p.Theme.Hand([]cards.Card{p.Hands[0].Cards[0], {}})
 }
 
 func (p Player) PlayerString() string {
@@ -573,12 +981,10 @@ func (p Player) PlayerString() string {
 	var response string
 	for index, hand := range p.Hands {
 		if hand.Action == ActionDoubleDown {
-			builder.WriteString(p.Name + " has ???: " + "[" + hand.Cards[0].String() + "]" + "[" + hand.Cards[1].String() + "]" + "[???]\n")
+			builder.WriteString(p.Name + " has ???: " + p.Theme.Hand([]cards.Card{hand.Cards[0], hand.Cards[1], {}}) + "\n")
 			response += builder.String()
 		} else {
-			for _, card := range p.Hands[index].Cards {
-				builder.WriteString("[" + card.String() + "]")
-			}
+			builder.WriteString(p.Theme.Hand(p.Hands[index].Cards))
 			str := []string{p.Name, " has ", fmt.Sprint(hand.Score()), ": ", builder.String(), "\n"}
 			response = strings.Join(str, "")
 		}
@@ -588,6 +994,152 @@ func (p Player) PlayerString() string {
 	return response
 }
 
+// IsFreeDouble reports whether the casino funds a double down on the hand
+func (p Player) IsFreeDouble(index int) bool {
+	return p.Variant == VariantFreeBet && p.Hands[index].IsFreeDouble()
+}
+
+// IsFreeSplit reports whether the casino funds a split of the hand
+func (p Player) IsFreeSplit(index int) bool {
+	return p.Variant == VariantFreeBet && p.Hands[index].IsFreeSplit()
+}
+
+// CanDouble reports whether the player can cover a double down on the
+// hand at index, or the casino pays for it
+func (p Player) CanDouble(index int) bool {
+	hand := p.Hands[index]
+	return len(hand.Cards) == 2 && (hand.Bet+hand.FreeBet <= p.Cash || p.IsFreeDouble(index))
+}
+
+// CanSplit reports whether the hand at index is a pair the player can
+// cover a split of, or the casino pays for it
+func (p Player) CanSplit(index int) bool {
+	hand := p.Hands[index]
+	return len(hand.Cards) == 2 && hand.Cards[0].Rank == hand.Cards[1].Rank && (hand.Bet+hand.FreeBet <= p.Cash || p.IsFreeSplit(index))
+}
+
+// AllowedActions lists the actions the hand at index can take
+func (p Player) AllowedActions(index int) []Action {
+	actions := []Action{ActionHit, ActionStand}
+	if p.CanDouble(index) {
+		actions = append(actions, ActionDoubleDown)
+	}
+	if p.CanSplit(index) {
+		actions = append(actions, ActionSplit)
+	}
+	return actions
+}
+
+// MaxSpots is the most betting spots a single player can occupy
+const MaxSpots = 3
+
+// SpotCount is the number of betting spots the player occupies
+func (p Player) SpotCount() int {
+	if p.Spots < 1 {
+		return 1
+	}
+	if p.Spots > MaxSpots {
+		return MaxSpots
+	}
+	return p.Spots
+}
+
+// SpotIndexes returns the index of the first hand in each spot
+func (p Player) SpotIndexes() []int {
+	indexes := []int{}
+	for index, hand := range p.Hands {
+		if index == 0 || hand.Spot != p.Hands[index-1].Spot {
+			indexes = append(indexes, index)
+		}
+	}
+	return indexes
+}
+
+// MaxBet is the largest bet the player can place on the spot of the
+// current hand when every hand in the spot carries an equal bet, up to
+// the table maximum
+func (p Player) MaxBet() int {
+	count := 0
+	for _, hand := range p.Hands {
+		if hand.Spot == p.Hands[p.HandIndex].Spot {
+			count++
+		}
+	}
+
+	max := p.Cash
+	if count > 0 {
+		max = p.Cash / count
+	}
+	if p.Limits.Max > 0 && max > p.Limits.Max {
+		max = p.Limits.Max
+	}
+	return max
+}
+
+// MinBet is the table minimum, at least $1
+func (p Player) MinBet() int {
+	if p.Limits.Min > 1 {
+		return p.Limits.Min
+	}
+	return 1
+}
+
+// PlaceBet places an equal bet on every hand in the spot of the hand at index
+func (p *Player) PlaceBet(index, bet int) {
+	spot := p.Hands[index].Spot
+	for _, hand := range p.Hands {
+		if hand.Spot == spot {
+			hand.Bet += bet
+			p.Cash -= bet
+		}
+	}
+}
+
+// RemoveEmptySpots drops hands that were not bet on.  A player without
+// any bets left quits, unless they are sitting the round out
+func (p *Player) RemoveEmptySpots() {
+	hands := []*Hand{}
+	for _, hand := range p.Hands {
+		if hand.Bet > 0 {
+			hands = append(hands, hand)
+		}
+	}
+	p.Hands = hands
+	p.HandIndex = 0
+
+	if len(p.Hands) == 0 && !p.SittingOut {
+		p.Action = ActionQuit
+	}
+}
+
+// SpotLabel names the spot of the hand for players with more than one spot
+func (p Player) SpotLabel(hand *Hand) string {
+	if p.SpotCount() == 1 {
+		return ""
+	}
+	return " spot #" + strconv.Itoa(hand.Spot+1)
+}
+
+// SwitchIndexes returns the index of the first hand in each spot that
+// holds the pair of hands dealt for Blackjack Switch
+func (p Player) SwitchIndexes() []int {
+	indexes := []int{}
+	for _, index := range p.SpotIndexes() {
+		if index+1 < len(p.Hands) && p.Hands[index+1].Spot == p.Hands[index].Spot {
+			indexes = append(indexes, index)
+		}
+	}
+	return indexes
+}
+
+// SwitchCards swaps the second cards of the pair of hands in the spot
+// starting at index
+func (p *Player) SwitchCards(index int) {
+	p.Hands[index].Cards[1], p.Hands[index+1].Cards[1] = p.Hands[index+1].Cards[1], p.Hands[index].Cards[1]
+	p.Hands[index].Switched = !p.Hands[index].Switched
+	p.Hands[index+1].Switched = !p.Hands[index+1].Switched
+}
+
 func (p Player) NextHandId() int {
 	return len(p.Hands) + 1
 }
@@ -599,7 +1151,17 @@ func (p *Player) SetDialog(dialog Dialog) error {
 		str := []string{p.Name, " has $", strconv.Itoa(p.Cash), " ", DialogPlayerMessage[dialog], " "}
 		p.Message = strings.Join(str, "")
 	case DialogPlaceYourBet:
-		str := []string{p.Name, " has $", strconv.Itoa(p.Cash), " ", DialogPlayerMessage[dialog], " ($1 to $", strconv.Itoa(p.Cash), " [$", strconv.Itoa(p.CurrentBet), "]): $"}
+		spot, skip := "", ""
+		if len(p.Hands) > 0 {
+			spot = p.SpotLabel(p.Hands[p.HandIndex])
+		}
+		if p.SpotCount() > 1 {
+			skip = ", $0 to skip"
+		}
+		str := []string{p.Name, " has $", strconv.Itoa(p.Cash), " ", DialogPlayerMessage[dialog], spot, " ($", strconv.Itoa(p.MinBet()), " to $", strconv.Itoa(p.MaxBet()), skip, " [$", strconv.Itoa(p.CurrentBet), "]): $"}
+		p.Message = strings.Join(str, "")
+	case DialogSwitchOrKeep:
+		str := []string{p.Name, " ", DialogPlayerMessage[dialog], " "}
 		p.Message = strings.Join(str, "")
 
 	default:
@@ -609,13 +1171,18 @@ func (p *Player) SetDialog(dialog Dialog) error {
 	return nil
 }
 
+// Hand is one of a player's hands.  A switched hand was dealt the other
+// hand's second card in Blackjack Switch, so its 21 is not a blackjack
 type Hand struct {
-	Id      int
-	Cards   []cards.Card
-	Bet     int
-	Action  Action
-	Outcome Outcome
-	Payout  int
+	Id       int
+	Cards    []cards.Card
+	Bet      int
+	Action   Action
+	Outcome  Outcome
+	Payout   int
+	FreeBet  int
+	Spot     int
+	Switched bool
 }
 
 func (h *Hand) Hit(output io.Writer, card cards.Card, name string) {
@@ -629,9 +1196,14 @@ func (h *Hand) Hit(output io.Writer, card cards.Card, name string) {
 
 }
 
-func (h *Hand) DoubleDown(output io.Writer, card cards.Card, name string) {
+func (h *Hand) DoubleDown(output io.Writer, card cards.Card, name string, free bool) {
 
-	h.Bet += h.Bet
+	wager := h.Bet + h.FreeBet
+	if free {
+		h.FreeBet += wager
+	} else {
+		h.Bet += wager
+	}
 	h.Cards = append(h.Cards, card)
 	h.Action = ActionStand
 	if h.Score() > 21 {
@@ -640,22 +1212,30 @@ func (h *Hand) DoubleDown(output io.Writer, card cards.Card, name string) {
 
 }
 
+// IsFreeDouble reports whether the hand is a two card hard 9, 10 or 11
+func (h Hand) IsFreeDouble() bool {
+	return len(h.Cards) == 2 && h.Score() == h.MinScore() && h.Score() >= 9 && h.Score() <= 11
+}
+
+// IsFreeSplit reports whether the hand is a pair other than tens
+func (h Hand) IsFreeSplit() bool {
+	return len(h.Cards) == 2 && h.Cards[0].Rank == h.Cards[1].Rank && h.Cards[0].Rank < cards.Ten
+}
+
 func (h Hand) ChooseAction() bool {
 	return h.Action != ActionQuit && h.Action != ActionStand && h.Outcome != OutcomeBlackjack && h.Outcome != OutcomeBust
 }
 
-func (h Hand) HandStringMulti(name string) string {
+func (h Hand) HandStringMulti(name string, theme Theme) string {
 
 	builder := strings.Builder{}
 	var response string
 
 	if h.Action == ActionDoubleDown {
-		builder.WriteString(name + " hand #" + strconv.Itoa(h.Id) + " has ??: " + "[" + h.Cards[0].String() + "]" + "[" + h.Cards[1].String() + "]" + "[???]\n")
+		builder.WriteString(name + " hand #" + strconv.Itoa(h.Id) + " has ??: " + theme.Hand([]cards.Card{h.Cards[0], h.Cards[1], {}}) + "\n")
 		response += builder.String()
 	} else {
-		for _, card := range h.Cards {
-			builder.WriteString("[" + card.String() + "]")
-		}
+		builder.WriteString(theme.Hand(h.Cards))
 		str := []string{name, " hand #", strconv.Itoa(h.Id), " has ", fmt.Sprint(h.Score()), ": ", builder.String(), "\n"}
 		response = strings.Join(str, "")
 	}
@@ -663,18 +1243,16 @@ func (h Hand) HandStringMulti(name string) string {
 	return response
 }
 
-func (h Hand) HandString(name string) string {
+func (h Hand) HandString(name string, theme Theme) string {
 
 	builder := strings.Builder{}
 	var response string
 
 	if h.Action == ActionDoubleDown {
-		builder.WriteString(name + " has ??: " + h.Cards[0].Render() + h.Cards[1].Render() + "[??]\n")
+		builder.WriteString(name + " has ??: " + theme.Hand([]cards.Card{h.Cards[0], h.Cards[1], {}}) + "\n")
 		response += builder.String()
 	} else {
-		for _, card := range h.Cards {
-			builder.WriteString(card.Render())
-		}
+		builder.WriteString(theme.Hand(h.Cards))
 		str := []string{name, " has ", fmt.Sprint(h.Score()), ": ", builder.String(), "\n"}
 		response = strings.Join(str, "")
 	}
@@ -682,17 +1260,25 @@ func (h Hand) HandString(name string) string {
 	return response
 }
 
-func (h Hand) DealerHandString() string {
+func (h Hand) DealerHandString(exposed bool, theme Theme) string {
+
+	if exposed {
+		return h.HandString("Dealer", theme)
+	}
 
 	builder := strings.Builder{}
 	var response string
 
-	builder.WriteString("Dealer has ??: " + "[??]" + h.Cards[1].Render() + "\n")
+	builder.WriteString("Dealer has ??: " + theme.Hand([]cards.Card{{}, h.Cards[1]}) + "\n")
 	response += builder.String()
 
 	return response
 }
 
+func (h Hand) IsBlackjack() bool {
+	return len(h.Cards) == 2 && h.Score() == 21
+}
+
 func (h Hand) Score() int {
 	minScore := h.MinScore()
 
@@ -722,6 +1308,17 @@ type Record struct {
 	HandsPlayed int
 }
 
+func (r *Record) Add(outcome Outcome) {
+	if outcome == OutcomeWin || outcome == OutcomeBlackjack {
+		r.Win += 1
+	} else if outcome == OutcomeTie {
+		r.Tie += 1
+	} else {
+		r.Lose += 1
+	}
+	r.HandsPlayed += 1
+}
+
 func (r Record) RecordString() string {
 
 	str := []string{
@@ -740,9 +1337,13 @@ func (r Record) RecordString() string {
 type CardCounter struct {
 	Count     int
 	TrueCount float64
+	Hidden    bool
 }
 
 func (c CardCounter) String() string {
+	if c.Hidden {
+		return "The count is hidden in practice mode"
+	}
 	return "Count: " + strconv.Itoa(c.Count) + ", True Count: " + strconv.FormatFloat(c.TrueCount, 'f', -1, 64)
 }
 
@@ -780,21 +1381,18 @@ func ScoreDealerHoleCard(card cards.Card) int {
 
 func HumanAction(output io.Writer, input io.Reader, player *Player, dealerCard cards.Card, index int, c CardCounter, stage Stage) Action {
 
-	// check to see if not enough to split or double
-	if player.Hands[index].Bet > player.Cash || len(player.Hands[index].Cards) > 2 {
-
-		player.Dialog = DialogHitOrStand
+	// check to see if enough to split or double, or if the casino pays for it
+	canDouble := player.CanDouble(index)
+	canSplit := player.CanSplit(index)
 
+	if canSplit && canDouble {
+		player.Dialog = DialogHitSplitDoubleStand
+	} else if canSplit {
+		player.Dialog = DialogHitSplitStand
+	} else if canDouble {
+		player.Dialog = DialogHitDoubleStand
 	} else {
-		// check if split is ok
-		if player.Hands[index].Cards[0].Rank == player.Hands[index].Cards[1].Rank {
-			player.Dialog = DialogHitSplitDoubleStand
-
-		} else {
-			// double ok
-			player.Dialog = DialogHitDoubleStand
-
-		}
+		player.Dialog = DialogHitOrStand
 	}
 	str := []string{
 		player.Name,
@@ -815,19 +1413,49 @@ func HumanBet(g *Game) error {
 	// hack
 	card := cards.Card{}
 
-	RenderPlayerMessage(g.output, g.ActivePlayer)
-	RenderPlayerInput(g.output, g.input, g.ActivePlayer, g.Stage, g.CardCounter, card)
+	output, input := g.PlayerOutput(g.ActivePlayer), g.PlayerInput(g.ActivePlayer)
+
+	RenderPlayerMessage(output, g.ActivePlayer)
+	RenderPlayerInput(output, input, g.ActivePlayer, g.Stage, g.CountFor(g.ActivePlayer), card)
 
-	if g.ActivePlayer.Action != ActionQuit {
-		g.ActivePlayer.SetDialog(DialogPlaceYourBet)
-		RenderPlayerMessage(g.output, g.ActivePlayer)
-		RenderPlayerInput(g.output, g.input, g.ActivePlayer, g.Stage, g.CardCounter, card)
+	if g.ActivePlayer.Action != ActionQuit && !g.ActivePlayer.SittingOut {
+		for _, index := range g.ActivePlayer.SpotIndexes() {
+			g.ActivePlayer.HandIndex = index
+			if g.ActivePlayer.MaxBet() < g.ActivePlayer.MinBet() {
+				break
+			}
+			g.ActivePlayer.SetDialog(DialogPlaceYourBet)
+			RenderPlayerMessage(output, g.ActivePlayer)
+			RenderPlayerInput(output, input, g.ActivePlayer, g.Stage, g.CountFor(g.ActivePlayer), card)
+		}
+		g.ActivePlayer.HandIndex = 0
 	}
 
 	return nil
 
 }
 
+func HumanSwitch(g *Game) error {
+
+	g.ActivePlayer.SetDialog(DialogSwitchOrKeep)
+
+	card := g.Dealer.Hands[0].Cards[1]
+	output, input := g.PlayerOutput(g.ActivePlayer), g.PlayerInput(g.ActivePlayer)
+
+	index := g.ActivePlayer.HandIndex
+	for _, hand := range g.ActivePlayer.Hands[index : index+2] {
+		fmt.Fprint(output, hand.HandStringMulti(g.ActivePlayer.Name+g.ActivePlayer.SpotLabel(hand), g.ActivePlayer.Theme))
+	}
+
+	RenderPlayerMessage(output, g.ActivePlayer)
+	err := RenderPlayerInput(output, input, g.ActivePlayer, g.Stage, g.CountFor(g.ActivePlayer), card)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // additional features
 // difficult to fake concreate...take interface instead
 // burn cards
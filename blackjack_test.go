@@ -3,6 +3,7 @@ package blackjack_test
 import (
 	"blackjack"
 	"bytes"
+	"io"
 	"math/rand"
 	"strings"
 	"testing"
@@ -552,10 +553,11 @@ func TestSplit(t *testing.T) {
 
 	index := 0
 
-	g.Players[0].Split(output, card1, card2, index)
+	g.Players[0].Split(output, card1, card2, index, false)
 
 	want := &blackjack.Player{
-		Cash: 98,
+		Cash:  98,
+		Theme: blackjack.ThemeMono,
 		Hands: []*blackjack.Hand{
 			{
 				Id: 1,
@@ -666,7 +668,7 @@ func TestBustOnDoubleDown(t *testing.T) {
 
 	card := g.Deal(output)
 
-	g.Players[0].Hands[0].DoubleDown(output, card, g.Players[0].Name)
+	g.Players[0].Hands[0].DoubleDown(output, card, g.Players[0].Name, false)
 
 	want := blackjack.OutcomeBust.String()
 
@@ -676,3 +678,483 @@ func TestBustOnDoubleDown(t *testing.T) {
 		t.Fatalf("want: %q, got: %q", want, got)
 	}
 }
+
+func TestFreeSplit(t *testing.T) {
+	t.Parallel()
+	output := &bytes.Buffer{}
+
+	stack := []cards.Card{
+		{Rank: cards.Nine, Suit: cards.Spade},
+		{Rank: cards.Four, Suit: cards.Diamond},
+	}
+
+	deck := cards.Deck{
+		Cards: stack,
+	}
+
+	g, err := blackjack.NewBlackjackGame(
+		blackjack.WithCustomDeck(deck),
+		blackjack.WithOutput(output),
+		blackjack.WithIncomingDeck(false),
+		blackjack.WithVariant(blackjack.VariantFreeBet),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := &blackjack.Player{
+		Cash: 99,
+		Hands: []*blackjack.Hand{
+			{
+				Id: 1,
+				Cards: []cards.Card{
+					{Rank: cards.Six, Suit: cards.Heart},
+					{Rank: cards.Six, Suit: cards.Club},
+				},
+				Bet: 1,
+			},
+		},
+	}
+
+	g.AddPlayer(p)
+
+	index := 0
+	free := p.IsFreeSplit(index)
+	if !free {
+		t.Fatal("want pair of sixes to be a free split")
+	}
+
+	card1 := g.Deal(output)
+	card2 := g.Deal(output)
+
+	p.Split(output, card1, card2, index, free)
+
+	wantCash := 99
+	gotCash := p.Cash
+
+	if wantCash != gotCash {
+		t.Fatalf("want: %d, got: %d", wantCash, gotCash)
+	}
+
+	wantFreeBet := 1
+	gotFreeBet := p.Hands[1].FreeBet
+
+	if wantFreeBet != gotFreeBet {
+		t.Fatalf("want: %d, got: %d", wantFreeBet, gotFreeBet)
+	}
+
+	wantBet := 0
+	gotBet := p.Hands[1].Bet
+
+	if wantBet != gotBet {
+		t.Fatalf("want: %d, got: %d", wantBet, gotBet)
+	}
+}
+
+func TestIsFreeDouble(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		cards       []cards.Card
+		free        bool
+		description string
+	}
+	tcs := []testCase{
+		{cards: []cards.Card{{Rank: cards.Six, Suit: cards.Club}, {Rank: cards.Three, Suit: cards.Club}}, free: true, description: "Hard 9"},
+		{cards: []cards.Card{{Rank: cards.Six, Suit: cards.Club}, {Rank: cards.Five, Suit: cards.Club}}, free: true, description: "Hard 11"},
+		{cards: []cards.Card{{Rank: cards.Six, Suit: cards.Club}, {Rank: cards.Two, Suit: cards.Club}}, free: false, description: "Hard 8"},
+		{cards: []cards.Card{{Rank: cards.Ace, Suit: cards.Club}, {Rank: cards.Nine, Suit: cards.Club}}, free: false, description: "Soft 20"},
+		{cards: []cards.Card{{Rank: cards.Two, Suit: cards.Club}, {Rank: cards.Three, Suit: cards.Club}, {Rank: cards.Five, Suit: cards.Club}}, free: false, description: "Three card 10"},
+	}
+
+	for _, tc := range tcs {
+		h := blackjack.Hand{Cards: tc.cards}
+
+		want := tc.free
+		got := h.IsFreeDouble()
+
+		if want != got {
+			t.Fatalf("%s: want: %v, got: %v", tc.description, want, got)
+		}
+	}
+}
+
+func TestFreeDoubleDownPayout(t *testing.T) {
+	t.Parallel()
+
+	p := &blackjack.Player{
+		Cash: 99,
+		Hands: []*blackjack.Hand{
+			{
+				Id:  1,
+				Bet: 1,
+				Cards: []cards.Card{
+					{Rank: cards.Six, Suit: cards.Club},
+					{Rank: cards.Four, Suit: cards.Club},
+				},
+			},
+		},
+	}
+
+	p.Hands[0].DoubleDown(io.Discard, cards.Card{Rank: cards.King, Suit: cards.Club}, p.Name, true)
+	p.Hands[0].Outcome = blackjack.OutcomeWin
+	p.Payout()
+
+	want := &blackjack.Player{
+		Cash: 102,
+		Hands: []*blackjack.Hand{
+			{
+				Id: 1,
+				Cards: []cards.Card{
+					{Rank: cards.Six, Suit: cards.Club},
+					{Rank: cards.Four, Suit: cards.Club},
+					{Rank: cards.King, Suit: cards.Club},
+				},
+				Action:  blackjack.ActionStand,
+				Outcome: blackjack.OutcomeWin,
+				Payout:  2,
+			},
+		},
+	}
+	got := p
+
+	if !cmp.Equal(want, got) {
+		t.Fatal(cmp.Diff(want, got))
+	}
+}
+
+func TestFreeBetDealerPushOn22(t *testing.T) {
+	t.Parallel()
+
+	stack := []cards.Card{
+		{Rank: cards.Ten, Suit: cards.Club},
+		{Rank: cards.Six, Suit: cards.Club},
+		{Rank: cards.Nine, Suit: cards.Club},
+		{Rank: cards.Six, Suit: cards.Spade},
+		{Rank: cards.King, Suit: cards.Club},
+	}
+
+	deck := cards.Deck{
+		Cards: stack,
+	}
+
+	output := &bytes.Buffer{}
+	g, err := blackjack.NewBlackjackGame(
+		blackjack.WithCustomDeck(deck),
+		blackjack.WithIncomingDeck(false),
+		blackjack.WithOutput(output),
+		blackjack.WithVariant(blackjack.VariantFreeBet),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := &blackjack.Player{
+		Name: "Planty",
+		Cash: 99,
+		Hands: []*blackjack.Hand{
+			{
+				Id:     1,
+				Bet:    1,
+				Action: blackjack.ActionStand,
+			},
+		},
+	}
+	g.AddPlayer(p)
+
+	g.OpeningDeal()
+	g.PlayHand(p)
+	g.DealerPlay()
+	g.Outcome(output)
+
+	want := blackjack.OutcomeTie
+	got := p.Hands[0].Outcome
+
+	if want != got {
+		t.Fatalf("want: %q, got: %q", want.String(), got.String())
+	}
+
+	wantCash := 100
+	gotCash := p.Cash
+
+	if wantCash != gotCash {
+		t.Fatalf("want: %d, got: %d", wantCash, gotCash)
+	}
+}
+
+func TestDoubleExposureOutcome(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		playerCards []cards.Card
+		dealerCards []cards.Card
+		tiePush     bool
+		outcome     blackjack.Outcome
+		cash        int
+		description string
+	}
+	tcs := []testCase{
+		{
+			playerCards: []cards.Card{{Rank: cards.Ten, Suit: cards.Club}, {Rank: cards.Nine, Suit: cards.Club}},
+			dealerCards: []cards.Card{{Rank: cards.King, Suit: cards.Club}, {Rank: cards.Nine, Suit: cards.Heart}},
+			outcome:     blackjack.OutcomeLose,
+			cash:        99,
+			description: "Dealer wins ties",
+		},
+		{
+			playerCards: []cards.Card{{Rank: cards.Ace, Suit: cards.Club}, {Rank: cards.King, Suit: cards.Club}},
+			dealerCards: []cards.Card{{Rank: cards.King, Suit: cards.Club}, {Rank: cards.Nine, Suit: cards.Heart}},
+			outcome:     blackjack.OutcomeBlackjack,
+			cash:        101,
+			description: "Blackjack pays even money",
+		},
+		{
+			playerCards: []cards.Card{{Rank: cards.Ace, Suit: cards.Club}, {Rank: cards.King, Suit: cards.Club}},
+			dealerCards: []cards.Card{{Rank: cards.Ace, Suit: cards.Heart}, {Rank: cards.Queen, Suit: cards.Heart}},
+			outcome:     blackjack.OutcomeLose,
+			cash:        99,
+			description: "Dealer wins blackjack ties",
+		},
+		{
+			playerCards: []cards.Card{{Rank: cards.Ace, Suit: cards.Club}, {Rank: cards.King, Suit: cards.Club}},
+			dealerCards: []cards.Card{{Rank: cards.Ace, Suit: cards.Heart}, {Rank: cards.Queen, Suit: cards.Heart}},
+			tiePush:     true,
+			outcome:     blackjack.OutcomeTie,
+			cash:        100,
+			description: "Blackjack ties push when allowed",
+		},
+	}
+
+	for _, tc := range tcs {
+		g, err := blackjack.NewBlackjackGame(
+			blackjack.WithOutput(io.Discard),
+			blackjack.WithVariant(blackjack.VariantDoubleExposure),
+			blackjack.WithBlackjackTiePush(tc.tiePush),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		p := &blackjack.Player{
+			Name: "Planty",
+			Cash: 99,
+			Hands: []*blackjack.Hand{
+				{
+					Id:     1,
+					Bet:    1,
+					Cards:  tc.playerCards,
+					Action: blackjack.ActionStand,
+				},
+			},
+		}
+		g.AddPlayer(p)
+		g.Dealer.Hands[0].Cards = tc.dealerCards
+
+		g.PlayHand(p)
+		g.Outcome(io.Discard)
+
+		want := tc.outcome
+		got := p.Hands[0].Outcome
+
+		if want != got {
+			t.Fatalf("%s: want: %q, got: %q", tc.description, want.String(), got.String())
+		}
+
+		wantCash := tc.cash
+		gotCash := p.Cash
+
+		if wantCash != gotCash {
+			t.Fatalf("%s: want: %d, got: %d", tc.description, wantCash, gotCash)
+		}
+	}
+}
+
+func TestDealerHandStringExposed(t *testing.T) {
+	t.Parallel()
+
+	h := blackjack.Hand{
+		Cards: []cards.Card{
+			{Rank: cards.King, Suit: cards.Club},
+			{Rank: cards.Six, Suit: cards.Club},
+		},
+	}
+
+	if strings.Contains(h.DealerHandString(true, blackjack.ThemeDefault), "??") {
+		t.Fatalf("want hole card exposed, got: %q", h.DealerHandString(true, blackjack.ThemeDefault))
+	}
+
+	if !strings.Contains(h.DealerHandString(false, blackjack.ThemeDefault), "??") {
+		t.Fatalf("want hole card hidden, got: %q", h.DealerHandString(false, blackjack.ThemeDefault))
+	}
+}
+
+func TestSpotRecords(t *testing.T) {
+	t.Parallel()
+
+	p := &blackjack.Player{
+		Spots: 2,
+		Hands: []*blackjack.Hand{
+			{Id: 1, Spot: 0, Outcome: blackjack.OutcomeWin},
+			{Id: 3, Spot: 0, Outcome: blackjack.OutcomeLose},
+			{Id: 2, Spot: 1, Outcome: blackjack.OutcomeTie},
+		},
+	}
+
+	p.SetWinLoseTie()
+
+	want := []blackjack.Record{
+		{Win: 1, Lose: 1, HandsPlayed: 2},
+		{Tie: 1, HandsPlayed: 1},
+	}
+	got := p.SpotRecords
+
+	if !cmp.Equal(want, got) {
+		t.Fatal(cmp.Diff(want, got))
+	}
+
+	wantRecord := blackjack.Record{Win: 1, Lose: 1, Tie: 1, HandsPlayed: 3}
+	gotRecord := p.Record
+
+	if !cmp.Equal(wantRecord, gotRecord) {
+		t.Fatal(cmp.Diff(wantRecord, gotRecord))
+	}
+}
+
+func TestSplitKeepsSpotOrder(t *testing.T) {
+	t.Parallel()
+
+	p := &blackjack.Player{
+		Cash:  98,
+		Spots: 2,
+		Hands: []*blackjack.Hand{
+			{
+				Id:    1,
+				Bet:   1,
+				Spot:  0,
+				Cards: []cards.Card{{Rank: cards.Eight, Suit: cards.Club}, {Rank: cards.Eight, Suit: cards.Heart}},
+			},
+			{
+				Id:    2,
+				Bet:   1,
+				Spot:  1,
+				Cards: []cards.Card{{Rank: cards.Ten, Suit: cards.Club}, {Rank: cards.Nine, Suit: cards.Heart}},
+			},
+		},
+	}
+
+	p.Split(io.Discard, cards.Card{Rank: cards.Two, Suit: cards.Club}, cards.Card{Rank: cards.Three, Suit: cards.Club}, 0, false)
+
+	want := []int{1, 3, 2}
+	got := []int{p.Hands[0].Id, p.Hands[1].Id, p.Hands[2].Id}
+
+	if !cmp.Equal(want, got) {
+		t.Fatal(cmp.Diff(want, got))
+	}
+
+	wantSpot := 0
+	gotSpot := p.Hands[1].Spot
+
+	if wantSpot != gotSpot {
+		t.Fatalf("want: %d, got: %d", wantSpot, gotSpot)
+	}
+}
+
+func TestSeating(t *testing.T) {
+	t.Parallel()
+
+	g, err := blackjack.NewBlackjackGame(
+		blackjack.WithSeatCount(3),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = g.SitPlayer(&blackjack.Player{Name: "Third Base"}, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = g.AddPlayer(&blackjack.Player{Name: "First Base"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = g.SitPlayer(&blackjack.Player{Name: "Late"}, 3)
+	if err == nil {
+		t.Fatal("want error sitting in a taken seat")
+	}
+
+	err = g.AddPlayer(&blackjack.Player{Name: "Middle"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = g.AddPlayer(&blackjack.Player{Name: "Standing"})
+	if err == nil {
+		t.Fatal("want error adding a player to a full table")
+	}
+
+	want := []string{"First Base", "Middle", "Third Base"}
+	got := []string{}
+	for _, player := range g.Players {
+		got = append(got, player.Name)
+	}
+
+	if !cmp.Equal(want, got) {
+		t.Fatal(cmp.Diff(want, got))
+	}
+}
+
+func TestNoMidShoeEntry(t *testing.T) {
+	t.Parallel()
+
+	g, err := blackjack.NewBlackjackGame(
+		blackjack.WithOutput(io.Discard),
+		blackjack.WithSeatCount(7),
+		blackjack.WithNoMidShoeEntry(true),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = g.AddPlayer(&blackjack.Player{Name: "Regular"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g.Deal(io.Discard)
+
+	p := &blackjack.Player{Name: "Newcomer"}
+	err = g.AddPlayer(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g.ResetPlayers()
+
+	if len(g.Players) != 1 || !p.Waiting {
+		t.Fatalf("want newcomer waiting for the next shoe, got %d players", len(g.Players))
+	}
+
+	g.ResetFieldsAfterIncomingDeck()
+	g.ResetPlayers()
+
+	want := 2
+	got := len(g.Players)
+
+	if want != got {
+		t.Fatalf("want: %d, got: %d", want, got)
+	}
+}
+
+func TestNewBlackjackGameReturnsOptionError(t *testing.T) {
+	t.Parallel()
+
+	_, err := blackjack.NewBlackjackGame(
+		blackjack.WithOutput(io.Discard),
+		blackjack.WithBetLimits(50, 10),
+	)
+	if err == nil {
+		t.Fatal("want an error for a maximum bet below the minimum")
+	}
+}
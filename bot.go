@@ -0,0 +1,462 @@
+package blackjack
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/mbarley333/cards"
+)
+
+// Bots are external programs that play a seat.  The game writes one
+// BotMessage per line to the bot's stdin and reads one BotReply per
+// line from its stdout for each message that needs an answer:
+//
+//	hello    -> {"id":1,"name":"Counter"}   name is optional
+//	bet      -> {"id":2,"bet":10}           bet 0 leaves the spot empty, or {"id":2,"action":"quit"}
+//	switch   -> {"id":3,"action":"switch"}  or keep
+//	action   -> {"id":4,"action":"hit"}     one of allowed
+//	outcome     no reply
+//	quit        no reply, the bot should exit
+//
+// A reply must carry the id of the message it answers.  A reply that
+// is late, unreadable or not allowed is logged and the fallback plays
+
+// BotMessage is a line sent to a bot.  Only the fields that make sense
+// for the message type are set
+type BotMessage struct {
+	Id         int        `json:"id,omitempty"`
+	Type       string     `json:"type"`
+	Name       string     `json:"name,omitempty"`
+	Variant    string     `json:"variant,omitempty"`
+	Decks      int        `json:"decks,omitempty"`
+	TimeoutMs  int64      `json:"timeoutMs,omitempty"`
+	Cash       int        `json:"cash,omitempty"`
+	Spot       int        `json:"spot,omitempty"`
+	MinBet     int        `json:"minBet,omitempty"`
+	MaxBet     int        `json:"maxBet,omitempty"`
+	Count      int        `json:"count,omitempty"`
+	TrueCount  float64    `json:"trueCount,omitempty"`
+	Hand       []string   `json:"hand,omitempty"`
+	Hands      [][]string `json:"hands,omitempty"`
+	HandIndex  int        `json:"handIndex,omitempty"`
+	HandId     int        `json:"handId,omitempty"`
+	Score      int        `json:"score,omitempty"`
+	Bet        int        `json:"bet,omitempty"`
+	DealerCard string     `json:"dealerCard,omitempty"`
+	Allowed    []string   `json:"allowed,omitempty"`
+	Outcome    string     `json:"outcome,omitempty"`
+	Payout     int        `json:"payout,omitempty"`
+}
+
+// BotReply is a line read from a bot
+type BotReply struct {
+	Id     int    `json:"id"`
+	Name   string `json:"name"`
+	Action string `json:"action"`
+	Bet    *int   `json:"bet"`
+}
+
+// Bot is a running bot program.  Fallback decides the hands the bot
+// fails to answer for in time
+type Bot struct {
+	Command  string
+	Timeout  time.Duration
+	Log      io.Writer
+	Fallback func(io.Writer, io.Reader, *Player, cards.Card, int, CardCounter, Stage) Action
+	Player   *Player
+	cmd      *exec.Cmd
+	stdin    io.WriteCloser
+	lines    chan string
+	nextId   int
+}
+
+func WithBot(command string) Option {
+	return func(g *Game) error {
+		g.BotCommands = append(g.BotCommands, command)
+		return nil
+	}
+}
+
+func WithBotTimeout(timeout time.Duration) Option {
+	return func(g *Game) error {
+		g.BotTimeout = timeout
+		return nil
+	}
+}
+
+// WithBotRounds is the number of rounds each bot plays.  The default
+// of 0 plays until the bot quits or runs out of cash
+func WithBotRounds(rounds int) Option {
+	return func(g *Game) error {
+		g.BotRounds = rounds
+		return nil
+	}
+}
+
+// WithBotLog is where late and illegal bot replies are logged.  The
+// default is the game output
+func WithBotLog(log io.Writer) Option {
+	return func(g *Game) error {
+		g.BotLog = log
+		return nil
+	}
+}
+
+// StartBot runs the bot program.  The command is split on spaces into
+// the program and its arguments.  What the bot writes to stderr goes to
+// our stderr, so bots can print their own debugging
+func StartBot(command string, timeout time.Duration, log io.Writer) (*Bot, error) {
+
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return nil, fmt.Errorf("bot command is empty")
+	}
+
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stderr = os.Stderr
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("unable to start bot %s, %s", command, err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("unable to start bot %s, %s", command, err)
+	}
+
+	err = cmd.Start()
+	if err != nil {
+		return nil, fmt.Errorf("unable to start bot %s, %s", command, err)
+	}
+
+	b := &Bot{
+		Command:  command,
+		Timeout:  timeout,
+		Log:      log,
+		Fallback: AiActionBasic,
+		cmd:      cmd,
+		stdin:    stdin,
+		lines:    make(chan string, 16),
+	}
+
+	go func() {
+		scanner := bufio.NewScanner(stdout)
+		for scanner.Scan() {
+			b.lines <- scanner.Text()
+		}
+		close(b.lines)
+	}()
+
+	return b, nil
+}
+
+// NewBotPlayer starts the bot and seats it as a custom AI player.  The
+// bot can choose its own name in reply to hello
+func (g *Game) NewBotPlayer(command string, index int) (*Player, error) {
+
+	log := g.BotLog
+	if log == nil {
+		log = g.output
+	}
+
+	timeout := g.BotTimeout
+	if timeout == 0 {
+		timeout = time.Second
+	}
+
+	b, err := StartBot(command, timeout, log)
+	if err != nil {
+		return nil, err
+	}
+
+	name := "Bot" + fmt.Sprint(index+1)
+	reply, ok := b.ask(name, BotMessage{
+		Type:      "hello",
+		Name:      name,
+		Variant:   g.Variant.String(),
+		Decks:     g.DeckCount,
+		TimeoutMs: timeout.Milliseconds(),
+	})
+	if ok && reply.Name != "" {
+		name = reply.Name
+	}
+
+	b.Player = &Player{
+		Name:           name,
+		Type:           PlayerTypeAiCustom,
+		Decide:         b.Decide,
+		Bet:            b.Bet,
+		Switch:         b.Switch,
+		AiRoundsToPlay: g.BotRounds,
+		Cash:           100,
+		Hands: []*Hand{
+			{Id: 1},
+		},
+	}
+
+	g.Bots = append(g.Bots, b)
+	g.Subscribe(b.notify)
+
+	return b.Player, nil
+}
+
+// IsBot is true for a player played by a bot process
+func (g *Game) IsBot(player *Player) bool {
+	for _, b := range g.Bots {
+		if b.Player == player {
+			return true
+		}
+	}
+	return false
+}
+
+// CloseBots tells every bot to quit and waits for them to exit
+func (g *Game) CloseBots() {
+	for _, b := range g.Bots {
+		b.Close()
+	}
+	g.Bots = nil
+}
+
+// Close sends quit and waits a moment for the bot to exit before
+// killing it
+func (b *Bot) Close() error {
+
+	if b.stdin == nil {
+		return nil
+	}
+	b.send(BotMessage{Type: "quit"})
+	b.stdin.Close()
+	b.stdin = nil
+
+	done := make(chan error, 1)
+	go func() {
+		done <- b.cmd.Wait()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(b.Timeout):
+		b.cmd.Process.Kill()
+		return <-done
+	}
+}
+
+func (b *Bot) send(message BotMessage) error {
+
+	if b.stdin == nil {
+		return fmt.Errorf("bot has quit")
+	}
+
+	data, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(b.stdin, "%s\n", data)
+	return err
+}
+
+func (b *Bot) logf(name, format string, args ...interface{}) {
+	fmt.Fprintf(b.Log, "bot %s: %s\n", name, fmt.Sprintf(format, args...))
+}
+
+// ask sends the message and waits for the reply to it.  Replies to
+// earlier messages that come in late are thrown away
+func (b *Bot) ask(name string, message BotMessage) (BotReply, bool) {
+
+	b.nextId++
+	message.Id = b.nextId
+
+	err := b.send(message)
+	if err != nil {
+		b.logf(name, "unable to send %s, %s", message.Type, err)
+		return BotReply{}, false
+	}
+
+	timer := time.NewTimer(b.Timeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case line, ok := <-b.lines:
+			if !ok {
+				b.logf(name, "exited before replying to %s", message.Type)
+				return BotReply{}, false
+			}
+
+			reply := BotReply{}
+			err := json.Unmarshal([]byte(line), &reply)
+			if err != nil {
+				b.logf(name, "unreadable reply %q", line)
+				return BotReply{}, false
+			}
+			if reply.Id != message.Id {
+				b.logf(name, "late reply to message %d thrown away", reply.Id)
+				continue
+			}
+			return reply, true
+
+		case <-timer.C:
+			b.logf(name, "no reply to %s within %s", message.Type, b.Timeout)
+			return BotReply{}, false
+		}
+	}
+}
+
+// notify tells the bot how its hands came out and that it has left
+func (b *Bot) notify(e Event) {
+
+	if b.Player == nil || e.Player != b.Player.Name {
+		return
+	}
+
+	switch e.Type {
+	case EventHandSettled:
+		b.send(BotMessage{
+			Type:    "outcome",
+			HandId:  e.HandId,
+			Bet:     e.Bet,
+			Outcome: e.Outcome.String(),
+			Payout:  e.Payout,
+			Cash:    e.Cash,
+		})
+	case EventPlayerLeft:
+		b.Close()
+	}
+}
+
+func cardNotations(hand []cards.Card) []string {
+	notations := []string{}
+	for _, card := range hand {
+		notations = append(notations, card.Notation())
+	}
+	return notations
+}
+
+// Bet asks the bot for a bet on each of its spots.  A bot that fails
+// to answer bets the table minimum as the other AI players do
+func (b *Bot) Bet(g *Game) error {
+
+	p := g.ActivePlayer
+	if p.AiRoundsToPlay > 0 && p.RoundsPlayed >= p.AiRoundsToPlay {
+		p.Action = ActionQuit
+		return nil
+	}
+
+	count := g.CountFor(p)
+	for spot, index := range p.SpotIndexes() {
+		p.HandIndex = index
+		minBet, maxBet := p.MinBet(), p.MaxBet()
+		if maxBet < minBet {
+			break
+		}
+
+		reply, ok := b.ask(p.Name, BotMessage{
+			Type:      "bet",
+			Cash:      p.Cash,
+			Spot:      spot + 1,
+			MinBet:    minBet,
+			MaxBet:    maxBet,
+			Count:     count.Count,
+			TrueCount: count.TrueCount,
+		})
+
+		bet := minBet
+		switch {
+		case !ok:
+			b.logf(p.Name, "betting %d", bet)
+		case strings.EqualFold(reply.Action, ActionNameMap[ActionQuit]):
+			p.Action = ActionQuit
+			p.HandIndex = 0
+			return nil
+		case reply.Bet == nil:
+			b.logf(p.Name, "no bet in reply, betting %d", bet)
+		case *reply.Bet < 0 || *reply.Bet > maxBet || (*reply.Bet > 0 && *reply.Bet < minBet):
+			b.logf(p.Name, "illegal bet %d, betting %d", *reply.Bet, bet)
+		default:
+			bet = *reply.Bet
+		}
+
+		if bet > 0 {
+			p.PlaceBet(index, bet)
+		}
+	}
+	p.HandIndex = 0
+
+	return nil
+}
+
+// Switch asks the bot whether to switch the top cards of the hands in
+// the spot, falling back on the AI switch
+func (b *Bot) Switch(g *Game) error {
+
+	p := g.ActivePlayer
+	index := p.HandIndex
+	reply, ok := b.ask(p.Name, BotMessage{
+		Type:       "switch",
+		Spot:       p.Hands[index].Spot + 1,
+		Hands:      [][]string{cardNotations(p.Hands[index].Cards), cardNotations(p.Hands[index+1].Cards)},
+		DealerCard: g.Dealer.Hands[0].Cards[1].Notation(),
+	})
+
+	if ok {
+		action, known := ParseAction(reply.Action)
+		if known && (action == ActionSwitch || action == ActionKeep) {
+			p.Action = action
+			return nil
+		}
+		b.logf(p.Name, "illegal switch %q", reply.Action)
+	}
+
+	return AiSwitch(g)
+}
+
+// Decide asks the bot for its action on the hand and plays the
+// fallback instead when the answer is missing or not allowed
+func (b *Bot) Decide(output io.Writer, input io.Reader, player *Player, dealerCard cards.Card, index int, c CardCounter, stage Stage) Action {
+
+	hand := player.Hands[index]
+	allowed := player.AllowedActions(index)
+
+	names := []string{}
+	for _, action := range allowed {
+		names = append(names, ActionNameMap[action])
+	}
+
+	reply, ok := b.ask(player.Name, BotMessage{
+		Type:       "action",
+		Cash:       player.Cash,
+		Hand:       cardNotations(hand.Cards),
+		HandIndex:  index,
+		Score:      hand.Score(),
+		Bet:        hand.Bet,
+		DealerCard: dealerCard.Notation(),
+		Allowed:    names,
+		Count:      c.Count,
+		TrueCount:  c.TrueCount,
+	})
+
+	if ok {
+		action, known := ParseAction(reply.Action)
+		if known && isAllowed(action, allowed) {
+			return action
+		}
+		b.logf(player.Name, "illegal action %q", reply.Action)
+	}
+
+	action := b.Fallback(output, input, player, dealerCard, index, c, stage)
+	if !isAllowed(action, allowed) {
+		action = FallbackAction(action)
+	}
+	b.logf(player.Name, "playing %s instead", action)
+
+	return action
+}
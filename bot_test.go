@@ -0,0 +1,211 @@
+package blackjack_test
+
+import (
+	"blackjack"
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mbarley333/cards"
+)
+
+// TestBotHelperProcess is not a test.  It is the bot program the bot
+// tests run, picked out by the mode after --
+func TestBotHelperProcess(t *testing.T) {
+
+	mode := ""
+	for i, arg := range os.Args {
+		if arg == "--" && i+1 < len(os.Args) {
+			mode = os.Args[i+1]
+		}
+	}
+	if mode == "" {
+		return
+	}
+
+	slow := mode == "slow"
+
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		message := blackjack.BotMessage{}
+		json.Unmarshal(scanner.Bytes(), &message)
+
+		reply := ""
+		switch message.Type {
+		case "hello":
+			reply = fmt.Sprintf(`{"id":%d,"name":"%sbot"}`, message.Id, mode)
+		case "bet":
+			bet := 5
+			if mode == "illegal" {
+				bet = 1000
+			}
+			reply = fmt.Sprintf(`{"id":%d,"bet":%d}`, message.Id, bet)
+		case "action":
+			action := "stand"
+			if mode == "illegal" {
+				action = "split"
+			}
+			// only the first action is late, so that the reply turns
+			// up while the next bet is being asked for
+			if slow {
+				time.Sleep(750 * time.Millisecond)
+				slow = false
+			}
+			reply = fmt.Sprintf(`{"id":%d,"action":"%s"}`, message.Id, action)
+		case "quit":
+			os.Exit(0)
+		}
+
+		if reply != "" {
+			fmt.Println(reply)
+		}
+	}
+	os.Exit(0)
+}
+
+func newBotGame(t *testing.T, mode string, rounds int, timeout time.Duration, output *strings.Builder) *blackjack.Game {
+
+	stack := []cards.Card{}
+	for i := 0; i < rounds; i++ {
+		stack = append(stack,
+			cards.Card{Rank: cards.Ten, Suit: cards.Club},
+			cards.Card{Rank: cards.Seven, Suit: cards.Club},
+			cards.Card{Rank: cards.Nine, Suit: cards.Club},
+			cards.Card{Rank: cards.King, Suit: cards.Spade},
+		)
+	}
+
+	command := fmt.Sprintf("%s -test.run=^TestBotHelperProcess$ -- %s", os.Args[0], mode)
+
+	g, err := blackjack.NewBlackjackGame(
+		blackjack.WithCustomDeck(cards.Deck{Cards: stack}),
+		blackjack.WithIncomingDeck(false),
+		blackjack.WithOutput(output),
+		blackjack.WithRendering(false),
+		blackjack.WithNumberOfHumanPlayers(0),
+		blackjack.WithBot(command),
+		blackjack.WithBotRounds(rounds),
+		blackjack.WithBotTimeout(timeout),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(g.CloseBots)
+
+	g.AddBlackjackPlayers()
+	if len(g.Players) != 1 {
+		t.Fatalf("want the bot seated, got: %s", output.String())
+	}
+
+	return g
+}
+
+func playOut(t *testing.T, g *blackjack.Game) *blackjack.Player {
+
+	player := g.Players[0]
+	for g.PlayAgain() {
+		err := g.PlayRound()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return player
+}
+
+func TestBotPlaysRound(t *testing.T) {
+	t.Parallel()
+
+	output := &strings.Builder{}
+	g := newBotGame(t, "stand", 1, time.Second, output)
+	player := playOut(t, g)
+
+	if player.Name != "standbot" {
+		t.Fatalf("want the name the bot chose, got %q", player.Name)
+	}
+
+	want := 105
+	got := player.Cash
+	if want != got {
+		t.Fatalf("want cash %d, got %d: %s", want, got, output.String())
+	}
+
+	if strings.Contains(output.String(), "bot standbot:") {
+		t.Fatalf("want nothing logged, got: %s", output.String())
+	}
+}
+
+func TestBotFallback(t *testing.T) {
+	t.Parallel()
+
+	output := &strings.Builder{}
+	g := newBotGame(t, "illegal", 1, time.Second, output)
+	player := playOut(t, g)
+
+	want := 101
+	got := player.Cash
+	if want != got {
+		t.Fatalf("want the fallback bet and stand to win %d, got %d: %s", want, got, output.String())
+	}
+
+	for _, log := range []string{"illegal bet 1000, betting 1", `illegal action "split"`, "playing Stand instead"} {
+		if !strings.Contains(output.String(), log) {
+			t.Fatalf("want %q logged, got: %s", log, output.String())
+		}
+	}
+}
+
+func TestBotTimeout(t *testing.T) {
+	t.Parallel()
+
+	output := &strings.Builder{}
+	g := newBotGame(t, "slow", 2, 500*time.Millisecond, output)
+	player := playOut(t, g)
+
+	want := 110
+	got := player.Cash
+	if want != got {
+		t.Fatalf("want a fallback stand and a bot stand to win %d, got %d: %s", want, got, output.String())
+	}
+
+	for _, log := range []string{"no reply to action within 500ms", "late reply to message 3 thrown away"} {
+		if !strings.Contains(output.String(), log) {
+			t.Fatalf("want %q logged, got: %s", log, output.String())
+		}
+	}
+}
+
+func TestBotNotSaved(t *testing.T) {
+	t.Parallel()
+
+	output := &strings.Builder{}
+	g := newBotGame(t, "stand", 1, time.Second, output)
+
+	buf := &bytes.Buffer{}
+	err := g.Save(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "standbot") {
+		t.Fatalf("want the bot left out of the save, got: %s", buf.String())
+	}
+
+	loaded, err := blackjack.LoadGame(buf,
+		blackjack.WithOutput(output),
+		blackjack.WithBot(g.BotCommands[0]),
+		blackjack.WithBotTimeout(time.Second),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(loaded.CloseBots)
+
+	loaded.AddBots()
+	if len(loaded.Players) != 1 || !loaded.IsBot(loaded.Players[0]) {
+		t.Fatalf("want the bot started again on resume, got: %s", output.String())
+	}
+}
@@ -1,14 +1,59 @@
 package blackjack
 
 import (
+	"fmt"
+
 	"github.com/mbarley333/cards"
 )
 
+type CountSystem int
+
+const (
+	CountSystemHiLo CountSystem = iota
+	CountSystemHiOptI
+	CountSystemOmegaII
+)
+
+var CountSystemMap = map[CountSystem]string{
+	CountSystemHiLo:    "Hi-Lo",
+	CountSystemHiOptI:  "Hi-Opt I",
+	CountSystemOmegaII: "Omega II",
+}
+
+func (c CountSystem) String() string {
+	return CountSystemMap[c]
+}
+
+var CountSystemInputMap = map[string]CountSystem{
+	"hilo":   CountSystemHiLo,
+	"hiopt1": CountSystemHiOptI,
+	"omega2": CountSystemOmegaII,
+}
+
+var CountSystemFuncMap = map[CountSystem]func(cards.Card, int, int, int) (int, float64){
+	CountSystemHiLo:    CountHiLo,
+	CountSystemHiOptI:  CountHiOptI,
+	CountSystemOmegaII: CountOmegaII,
+}
+
+// WithCountSystem sets the counting system the game keeps the count with
+func WithCountSystem(system CountSystem) Option {
+	return func(g *Game) error {
+		count, ok := CountSystemFuncMap[system]
+		if !ok {
+			return fmt.Errorf("unknown count system %d", system)
+		}
+		g.CountSystem = system
+		g.CountCards = count
+		return nil
+	}
+}
+
 func CountHiLo(card cards.Card, count int, numberCardsDealt int, deckCount int) (int, float64) {
 
 	if card.Rank >= 2 && card.Rank <= 6 {
 		count += 1
-	} else if card.Rank >= 10 {
+	} else if card.Rank >= 10 || card.Rank == cards.Ace {
 		count -= 1
 	}
 
@@ -17,3 +62,37 @@ func CountHiLo(card cards.Card, count int, numberCardsDealt int, deckCount int)
 
 	return count, trueCount
 }
+
+// CountHiOptI counts 3 to 6 up and tens down, leaving aces neutral
+func CountHiOptI(card cards.Card, count int, numberCardsDealt int, deckCount int) (int, float64) {
+
+	if card.Rank >= 3 && card.Rank <= 6 {
+		count += 1
+	} else if card.Rank >= 10 {
+		count -= 1
+	}
+
+	return count, TrueCount(count, numberCardsDealt, deckCount)
+}
+
+// CountOmegaII is a level two count, with 4 to 6 and tens counting double
+func CountOmegaII(card cards.Card, count int, numberCardsDealt int, deckCount int) (int, float64) {
+
+	switch card.Rank {
+	case 2, 3, 7:
+		count += 1
+	case 4, 5, 6:
+		count += 2
+	case 9:
+		count -= 1
+	case cards.Ten, cards.Jack, cards.Queen, cards.King:
+		count -= 2
+	}
+
+	return count, TrueCount(count, numberCardsDealt, deckCount)
+}
+
+// TrueCount is the running count per deck left in the shoe
+func TrueCount(count int, numberCardsDealt int, deckCount int) float64 {
+	return float64(count) / (float64(deckCount) - float64(numberCardsDealt)/52.0)
+}
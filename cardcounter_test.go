@@ -1,5 +1,12 @@
 package blackjack_test
 
+import (
+	"blackjack"
+	"testing"
+
+	"github.com/mbarley333/cards"
+)
+
 // func TestCardCounting(t *testing.T) {
 // 	t.Parallel()
 
@@ -52,3 +59,30 @@ package blackjack_test
 // 	}
 
 // }
+
+func TestCountSystems(t *testing.T) {
+	t.Parallel()
+
+	deck := cards.NewDeck(cards.WithNumberOfDecks(1))
+
+	for system, count := range blackjack.CountSystemFuncMap {
+		running := 0
+		for i, card := range deck.Cards {
+			running, _ = count(card, running, i+1, 1)
+		}
+		if running != 0 {
+			t.Fatalf("%s want a balanced count of 0 after a full deck, got: %d", system, running)
+		}
+	}
+}
+
+func TestTrueCount(t *testing.T) {
+	t.Parallel()
+
+	want := 2.0
+	got := blackjack.TrueCount(6, 156, 6)
+
+	if want != got {
+		t.Fatalf("want: %f, got: %f", want, got)
+	}
+}
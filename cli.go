@@ -2,9 +2,13 @@ package blackjack
 
 import (
 	"bufio"
+	"context"
 	"flag"
 	"fmt"
 	"io"
+	"math/rand"
+	"net"
+	"net/http"
 	"os"
 	"strconv"
 	"strings"
@@ -15,44 +19,714 @@ import (
 
 func RunCLI() {
 
+	if len(os.Args) > 1 && os.Args[1] == "replay" {
+		RunReplay(os.Args[2:])
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "drill" {
+		RunDrill(os.Args[2:])
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "count" {
+		RunCountDrill(os.Args[2:])
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "tournament" {
+		RunTournament(os.Args[2:])
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "serve" {
+		RunServe(os.Args[2:])
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "connect" {
+		RunConnect(os.Args[2:])
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "lobby" {
+		RunLobby(os.Args[2:])
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "api" {
+		RunAPI(os.Args[2:])
+		return
+	}
+
 	flag.Usage = help
 
 	humanPlayersPtr := flag.Int("humanPlayers", 1, "Number of human players.  Default is 1")
 	aiPlayersPtr := flag.Int("aiPlayers", 0, "Number of AI players.  Default is 0")
 	deckCountPtr := flag.Int("deckCount", 6, "Number of decks in shoe.  Default is 6")
+	variantPtr := flag.String("variant", "classic", "Rules variant (classic, freebet, doubleexposure or switch).  Default is classic")
+	blackjackTiePushPtr := flag.Bool("blackjackTiePush", false, "Tied blackjacks push in Double Exposure.  Default is false")
+	seatsPtr := flag.Int("seats", 7, "Number of seats at the table.  Default is 7")
+	noMidShoeEntryPtr := flag.Bool("noMidShoeEntry", false, "New players wait for the next shoe.  Default is false")
+	seedPtr := flag.Int64("seed", 0, "Seed for shuffling the shoe.  Default is random")
+	handHistoryPtr := flag.String("handHistory", "", "File to append the hand history to.  Default is none")
+	saveFilePtr := flag.String("saveFile", "blackjack.save", "File the game is saved to between rounds.  Default is blackjack.save")
+	resumePtr := flag.Bool("resume", false, "Resume the game in the save file.  A new game is not started over one that is saved.  Default is false")
+	profilesPtr := flag.String("profiles", "blackjack.profiles", "File human player profiles are kept in.  Default is blackjack.profiles")
+	showProfilePtr := flag.String("showProfile", "", "Show the lifetime report of a player profile and exit")
+	strategyPtr := flag.String("strategy", "basic", "Strategy human decisions are checked against (basic or count).  Default is basic")
+	countSystemPtr := flag.String("countSystem", "hilo", "Counting system (hilo, hiopt1 or omega2).  Default is hilo")
+	countPracticePtr := flag.Bool("countPractice", false, "Hide the count and check the players' own count.  Default is false")
+	countCheatPtr := flag.Bool("countCheat", false, "Still show the count with c in count practice.  Default is false")
+	bots := commandList{}
+	flag.Var(&bots, "bot", "Command that runs a bot player, repeat for more bots.  Default is none")
+	botTimeoutPtr := flag.Int("botTimeout", 1000, "Milliseconds a bot has to answer.  Default is 1000")
+	botRoundsPtr := flag.Int("botRounds", 0, "Number of rounds each bot plays.  Default is until it quits")
+	botLogPtr := flag.String("botLog", "", "File late and illegal bot replies are logged to.  Default is the table")
+	decisionTimeoutPtr := flag.Int("decisionTimeout", 0, "Seconds a human player has for each decision.  Default is no limit")
+	timeBankPtr := flag.Int("timeBank", 30, "Extra seconds each human player can draw on once a decision's time is up.  Default is 30")
+	timeoutPlayPtr := flag.String("timeoutPlay", "stand", "Play for a human player out of time (stand or basic).  Default is stand")
+	fullScreenPtr := flag.Bool("fullScreen", false, "Draw the table full screen, redrawn in place.  Default is false")
+	showCountPtr := flag.Bool("showCount", false, "Show the running count on the full screen table.  Default is false")
+	themePtr := flag.String("theme", "default", "How cards are drawn (default, color, mono, ascii or art).  Default is color on a terminal unless NO_COLOR is set")
+	pacePtr := flag.String("pace", "default", "How quickly cards are dealt (normal, fast, instant or step).  Default is normal with human players and instant without")
 
 	flag.Parse()
 
-	g, err := NewBlackjackGameWithArgs(*humanPlayersPtr, *aiPlayersPtr, *deckCountPtr)
+	variant, ok := VariantInputMap[strings.ToLower(*variantPtr)]
+	if !ok {
+		fmt.Println(fmt.Errorf("unknown variant, %s", *variantPtr))
+		os.Exit(1)
+	}
+
+	strategy, ok := StrategyInputMap[strings.ToLower(*strategyPtr)]
+	if !ok {
+		fmt.Println(fmt.Errorf("unknown strategy, %s", *strategyPtr))
+		os.Exit(1)
+	}
+
+	countSystem, ok := CountSystemInputMap[strings.ToLower(*countSystemPtr)]
+	if !ok {
+		fmt.Println(fmt.Errorf("unknown count system, %s", *countSystemPtr))
+		os.Exit(1)
+	}
+
+	theme, ok := ThemeInputMap[strings.ToLower(*themePtr)]
+	if !ok {
+		fmt.Println(fmt.Errorf("unknown theme, %s", *themePtr))
+		os.Exit(1)
+	}
+
+	pace, ok := PaceInputMap[strings.ToLower(*pacePtr)]
+	if !ok {
+		fmt.Println(fmt.Errorf("unknown pace, %s", *pacePtr))
+		os.Exit(1)
+	}
+
+	timeoutOpts, err := TimeoutOptions(*decisionTimeoutPtr, *timeBankPtr, *timeoutPlayPtr)
 	if err != nil {
-		fmt.Println(fmt.Errorf("cannot create new blackjack game, %s", err))
+		fmt.Println(err)
+		os.Exit(1)
 	}
 
-	g.AddBlackjackPlayers()
+	opts := []Option{WithStrategy(strategy), WithTheme(theme), WithPace(pace)}
+	opts = append(opts, timeoutOpts...)
 
-	for g.PlayAgain() {
+	if *countPracticePtr {
+		opts = append(opts, WithCountPractice(*countCheatPtr))
+	}
 
-		g.ResetPlayers()
-		g.Betting()
-		g.Players = g.RemoveQuitPlayers()
-		if g.PlayAgain() {
+	// a pipe or a file gets the line by line view
+	if *fullScreenPtr && IsTerminal(os.Stdout) {
+		opts = append(opts, WithFullScreen(*showCountPtr))
+	}
+
+	if *profilesPtr != "" {
+		store, err := OpenProfileStore(*profilesPtr)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		if *showProfilePtr != "" {
+			profile, ok := store.Profiles[*showProfilePtr]
+			if !ok {
+				fmt.Printf("no profile for %s\n", *showProfilePtr)
+				os.Exit(1)
+			}
+			profile.Report(os.Stdout)
+			return
+		}
 
-			g.OpeningDeal()
-			g.Deciding()
-			g.DealerPlay()
-			g.Outcome(g.output)
+		opts = append(opts, WithProfiles(store))
+	}
+
+	opts = append(opts, WithBotTimeout(time.Duration(*botTimeoutPtr)*time.Millisecond), WithBotRounds(*botRoundsPtr))
+	for _, command := range bots {
+		opts = append(opts, WithBot(command))
+	}
+
+	if *botLogPtr != "" {
+		file, err := os.OpenFile(*botLogPtr, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			fmt.Println(fmt.Errorf("cannot open bot log file, %s", err))
+			os.Exit(1)
 		}
+		defer file.Close()
+		opts = append(opts, WithBotLog(file))
 	}
+
+	if *handHistoryPtr != "" {
+		file, err := os.OpenFile(*handHistoryPtr, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			fmt.Println(fmt.Errorf("cannot open hand history file, %s", err))
+			os.Exit(1)
+		}
+		defer file.Close()
+		opts = append(opts, WithHandHistory(file))
+	}
+
+	var g *Game
+
+	if *resumePtr {
+		g, err = ResumeGame(*saveFilePtr, opts...)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		// bots are not saved, they are started again from their commands
+		g.AddBots()
+	} else {
+		// a game left in the save file is not overwritten by a new one
+		err = CheckSaveFile(*saveFilePtr)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		opts = append(opts,
+			WithCountSystem(countSystem),
+			WithVariant(variant),
+			WithBlackjackTiePush(*blackjackTiePushPtr),
+			WithSeatCount(*seatsPtr),
+			WithNoMidShoeEntry(*noMidShoeEntryPtr),
+		)
+
+		if *seedPtr != 0 {
+			opts = append(opts, WithSeed(*seedPtr))
+		}
+
+		g, err = NewBlackjackGameWithArgs(*humanPlayersPtr, *aiPlayersPtr, *deckCountPtr, opts...)
+		if err != nil {
+			fmt.Println(fmt.Errorf("cannot create new blackjack game, %s", err))
+			os.Exit(1)
+		}
+
+		g.AddBlackjackPlayers()
+	}
+
+	for g.PlayAgain() {
+		err = g.PlayRound()
+		if err != nil {
+			fmt.Fprintln(g.output, err)
+			break
+		}
+
+		err = g.SaveFile(*saveFilePtr)
+		if err != nil {
+			fmt.Fprintln(g.output, err)
+		}
+	}
+	g.CloseBots()
+	g.RenderAccuracy(g.output)
+	g.RenderPractice(g.output)
 	fmt.Fprintln(g.output, "No players left in game.  Exiting...")
+
+	// nothing is left to resume
+	os.Remove(*saveFilePtr)
+}
+
+// commandList collects a flag given more than once
+type commandList []string
+
+func (c *commandList) String() string {
+	return strings.Join(*c, ", ")
+}
+
+func (c *commandList) Set(command string) error {
+	*c = append(*c, command)
+	return nil
+}
+
+// CheckSaveFile returns an error when there is already a game saved in
+// path
+func CheckSaveFile(path string) error {
+	_, err := os.Stat(path)
+	if err == nil {
+		return fmt.Errorf("a game is saved in %s, carry on with -resume or remove the file to start a new game", path)
+	}
+	return nil
+}
+
+// ResumeGame loads the game saved in path
+func ResumeGame(path string, opts ...Option) (*Game, error) {
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open save file, %s", err)
+	}
+	defer file.Close()
+
+	g, err := LoadGame(file, opts...)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(g.output, "Resuming round %d with %d players\n", g.Round+1, len(g.Players)+len(g.WaitingPlayers))
+
+	return g, nil
 }
 
-func NewBlackjackGameWithArgs(humanPlayers, aiPlayers, deckCount int) (*Game, error) {
+// RunReplay replays a hand history file and exits with an error when
+// the engine no longer plays the session the way it was recorded
+func RunReplay(args []string) {
+
+	flags := flag.NewFlagSet("replay", flag.ExitOnError)
+	flags.Usage = help
+
+	filePtr := flags.String("file", "", "Hand history file to replay")
+	seedPtr := flags.Int64("seed", 0, "Seed the session was dealt with.  Default is the seed in the file")
+	roundPtr := flags.Int("round", 0, "Last round to replay.  Default is every round")
+	stepPtr := flags.Bool("step", false, "Wait for enter after each round.  Default is false")
+	pacePtr := flags.String("pace", "instant", "How quickly cards are dealt (normal, fast, instant or step).  Default is instant")
+
+	flags.Parse(args)
+
+	pace, ok := PaceInputMap[strings.ToLower(*pacePtr)]
+	if !ok {
+		fmt.Println(fmt.Errorf("unknown pace, %s", *pacePtr))
+		os.Exit(1)
+	}
+
+	file, err := os.Open(*filePtr)
+	if err != nil {
+		fmt.Println(fmt.Errorf("cannot open hand history file, %s", err))
+		os.Exit(1)
+	}
+	defer file.Close()
+
+	rounds, err := ReadHandHistory(file)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	r := NewReplay(rounds)
+	err = r.Run(os.Stdout, os.Stdin, *seedPtr, *roundPtr, *stepPtr, WithPace(pace))
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	if len(r.Mismatches) > 0 {
+		fmt.Println("Replay does not match the hand history:")
+		for _, mismatch := range r.Mismatches {
+			fmt.Println(mismatch)
+		}
+		os.Exit(1)
+	}
+	fmt.Println("Replay matches the hand history")
+}
+
+// RunDrill quizzes the player on basic strategy
+func RunDrill(args []string) {
+
+	flags := flag.NewFlagSet("drill", flag.ExitOnError)
+	flags.Usage = help
+
+	handsPtr := flags.Int("hands", 20, "Number of hands to drill.  Default is 20")
+	weightedPtr := flags.Bool("weighted", false, "Favour difficult hands and the ones answered wrong.  Default is false")
+	strategyPtr := flags.String("strategy", "basic", "Strategy the answers are checked against (basic or count).  Default is basic")
+	seedPtr := flags.Int64("seed", 0, "Seed for dealing the hands.  Default is random")
+
+	flags.Parse(args)
+
+	strategy, ok := StrategyInputMap[strings.ToLower(*strategyPtr)]
+	if !ok {
+		fmt.Println(fmt.Errorf("unknown strategy, %s", *strategyPtr))
+		os.Exit(1)
+	}
+
+	seed := *seedPtr
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	d := NewDrill(os.Stdout, os.Stdin, rand.New(rand.NewSource(seed)))
+	d.Strategy = strategy
+	d.Weighted = *weightedPtr
+	d.Run(*handsPtr)
+}
+
+// RunCountDrill practices keeping the count
+func RunCountDrill(args []string) {
+
+	flags := flag.NewFlagSet("count", flag.ExitOnError)
+	flags.Usage = help
+
+	modePtr := flags.String("mode", "flash", "Drill to run (flash, countdown or convert).  Default is flash")
+	roundsPtr := flags.Int("rounds", 5, "Number of rounds to drill.  Default is 5")
+	cardsPtr := flags.Int("cards", 10, "Number of cards flashed each round.  Default is 10")
+	groupPtr := flags.Int("group", 1, "Number of cards in each flash.  Default is 1")
+	speedPtr := flags.Int("speed", 1000, "Milliseconds each flash is shown.  Default is 1000")
+	progressivePtr := flags.Bool("progressive", false, "Speed up after each round counted right.  Default is false")
+	systemPtr := flags.String("system", "hilo", "Counting system (hilo, hiopt1 or omega2).  Default is hilo")
+	deckCountPtr := flags.Int("deckCount", 6, "Number of decks in shoe.  Default is 6")
+	seedPtr := flags.Int64("seed", 0, "Seed for shuffling the shoe.  Default is random")
+
+	flags.Parse(args)
+
+	system, ok := CountSystemInputMap[strings.ToLower(*systemPtr)]
+	if !ok {
+		fmt.Println(fmt.Errorf("unknown count system, %s", *systemPtr))
+		os.Exit(1)
+	}
+
+	seed := *seedPtr
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
 
 	g, err := NewBlackjackGame(
+		WithRendering(false),
+		WithOutput(io.Discard),
+		WithSeed(seed),
+		WithDeckCount(*deckCountPtr),
+		WithCountSystem(system),
+	)
+	if err != nil {
+		fmt.Println(fmt.Errorf("cannot create shoe for count drill, %s", err))
+		os.Exit(1)
+	}
+
+	d := NewCountDrill(g, os.Stdout, os.Stdin, rand.New(rand.NewSource(seed)))
+	d.Speed = time.Duration(*speedPtr) * time.Millisecond
+	d.Group = *groupPtr
+	d.Progressive = *progressivePtr
+
+	for i := 0; i < *roundsPtr; i++ {
+		switch *modePtr {
+		case "countdown":
+			d.Countdown()
+		case "convert":
+			d.Conversion()
+		default:
+			d.Round(*cardsPtr)
+		}
+	}
+
+	d.Report(os.Stdout)
+}
+
+// RunTournament plays an elimination tournament
+func RunTournament(args []string) {
+
+	flags := flag.NewFlagSet("tournament", flag.ExitOnError)
+	flags.Usage = help
+
+	humanPlayersPtr := flags.Int("humanPlayers", 1, "Number of human players.  Default is 1")
+	aiPlayersPtr := flags.Int("aiPlayers", 7, "Number of AI players.  Default is 7")
+	chipsPtr := flags.Int("chips", 1000, "Chips every player starts each round with.  Default is 1000")
+	handsPtr := flags.Int("hands", 10, "Number of hands in each round.  Default is 10")
+	tableSizePtr := flags.Int("tableSize", 6, "Most players at a table.  Default is 6")
+	advancePtr := flags.Int("advance", 2, "Number of players who advance from each table.  Default is 2")
+	minBetPtr := flags.Int("minBet", 10, "Table minimum bet.  Default is 10")
+	maxBetPtr := flags.Int("maxBet", 500, "Table maximum bet.  Default is 500")
+	secretBetsPtr := flags.Bool("secretBets", false, "Keep bets on the final hand of each round secret.  Default is false")
+	deckCountPtr := flags.Int("deckCount", 6, "Number of decks in shoe.  Default is 6")
+	variantPtr := flags.String("variant", "classic", "Rules variant (classic, freebet, doubleexposure or switch).  Default is classic")
+	seedPtr := flags.Int64("seed", 0, "Seed for shuffling the shoe.  Default is random")
+	themePtr := flags.String("theme", "default", "How cards are drawn (default, color, mono, ascii or art).  Default is color on a terminal unless NO_COLOR is set")
+	pacePtr := flags.String("pace", "default", "How quickly cards are dealt (normal, fast, instant or step).  Default is normal with human players and instant without")
+
+	flags.Parse(args)
+
+	variant, ok := VariantInputMap[strings.ToLower(*variantPtr)]
+	if !ok {
+		fmt.Println(fmt.Errorf("unknown variant, %s", *variantPtr))
+		os.Exit(1)
+	}
+
+	theme, ok := ThemeInputMap[strings.ToLower(*themePtr)]
+	if !ok {
+		fmt.Println(fmt.Errorf("unknown theme, %s", *themePtr))
+		os.Exit(1)
+	}
+
+	pace, ok := PaceInputMap[strings.ToLower(*pacePtr)]
+	if !ok {
+		fmt.Println(fmt.Errorf("unknown pace, %s", *pacePtr))
+		os.Exit(1)
+	}
+
+	opts := []Option{
+		WithDeckCount(*deckCountPtr),
+		WithVariant(variant),
+		WithTheme(theme),
+		WithPace(pace),
+	}
+	if *seedPtr != 0 {
+		opts = append(opts, WithSeed(*seedPtr))
+	}
+
+	t := NewTournament(os.Stdout, nil, opts...)
+	t.Chips = *chipsPtr
+	t.Hands = *handsPtr
+	t.TableSize = *tableSizePtr
+	t.Advance = *advancePtr
+	t.Limits = BetLimits{Min: *minBetPtr, Max: *maxBetPtr}
+	t.SecretBets = *secretBetsPtr
+
+	// every table reads the human players from the same buffer
+	input := BufferedReader(os.Stdin)
+	for i := 0; i < *humanPlayersPtr; i++ {
+		player := NewHumanPlayer(os.Stdout, input, i)
+		player.Input = input
+		t.Players = append(t.Players, player)
+	}
+	for i := 0; i < *aiPlayersPtr; i++ {
+		t.Players = append(t.Players, t.TournamentAiPlayer("AiPlayer"+strconv.Itoa(i+1)))
+	}
+
+	err := t.Run()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+// RunServe hosts a table for remote players
+func RunServe(args []string) {
+
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	flags.Usage = help
+
+	addrPtr := flags.String("addr", ":4000", "Address to serve the table on.  Default is :4000")
+	deckCountPtr := flags.Int("deckCount", 6, "Number of decks in shoe.  Default is 6")
+	variantPtr := flags.String("variant", "classic", "Rules variant (classic, freebet, doubleexposure or switch).  Default is classic")
+	seatsPtr := flags.Int("seats", 7, "Number of seats at the table.  Default is 7")
+	seedPtr := flags.Int64("seed", 0, "Seed for shuffling the shoe.  Default is random")
+	reconnectPtr := flags.Int("reconnectWait", 30, "Seconds the table waits for a dropped player before sitting them out.  Default is 30")
+	decisionTimeoutPtr := flags.Int("decisionTimeout", 30, "Seconds a player has for each decision.  Default is 30")
+	timeBankPtr := flags.Int("timeBank", 30, "Extra seconds each player can draw on once a decision's time is up.  Default is 30")
+	timeoutPlayPtr := flags.String("timeoutPlay", "stand", "Play for a player out of time (stand or basic).  Default is stand")
+
+	flags.Parse(args)
+
+	variant, ok := VariantInputMap[strings.ToLower(*variantPtr)]
+	if !ok {
+		fmt.Println(fmt.Errorf("unknown variant, %s", *variantPtr))
+		os.Exit(1)
+	}
+
+	timeoutOpts, err := TimeoutOptions(*decisionTimeoutPtr, *timeBankPtr, *timeoutPlayPtr)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	listener, err := net.Listen("tcp", *addrPtr)
+	if err != nil {
+		fmt.Println(fmt.Errorf("cannot listen on %s, %s", *addrPtr, err))
+		os.Exit(1)
+	}
+
+	opts := []Option{
+		WithDeckCount(*deckCountPtr),
+		WithVariant(variant),
+		WithSeatCount(*seatsPtr),
+	}
+	opts = append(opts, timeoutOpts...)
+	if *seedPtr != 0 {
+		opts = append(opts, WithSeed(*seedPtr))
+	}
+
+	s, err := NewServer(listener, opts...)
+	if err != nil {
+		fmt.Println(fmt.Errorf("cannot create table, %s", err))
+		os.Exit(1)
+	}
+	s.ReconnectWait = time.Duration(*reconnectPtr) * time.Second
+
+	fmt.Printf("Serving blackjack on %s\n", listener.Addr())
+	err = s.Run()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+// RunConnect plays at a table hosted by serve
+func RunConnect(args []string) {
+
+	flags := flag.NewFlagSet("connect", flag.ExitOnError)
+	flags.Usage = help
+
+	addrPtr := flags.String("addr", "localhost:4000", "Address of the table.  Default is localhost:4000")
+	watchPtr := flags.Bool("watch", false, "Watch the table without a seat.  Default is false")
+	delayPtr := flags.Int("delay", 0, "Milliseconds each event is held back while watching.  Default is 0")
+
+	flags.Parse(args)
+
+	conn, err := net.Dial("tcp", *addrPtr)
+	if err != nil {
+		fmt.Println(fmt.Errorf("cannot connect to %s, %s", *addrPtr, err))
+		os.Exit(1)
+	}
+	defer conn.Close()
+
+	if *watchPtr {
+		err = Watch(conn, os.Stdout, time.Duration(*delayPtr)*time.Millisecond)
+	} else {
+		err = Connect(conn, os.Stdout, os.Stdin)
+	}
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+// RunLobby serves several tables behind a lobby
+func RunLobby(args []string) {
+
+	flags := flag.NewFlagSet("lobby", flag.ExitOnError)
+	flags.Usage = help
+
+	addrPtr := flags.String("addr", ":4000", "Address to serve the lobby on.  Default is :4000")
+	tables := commandList{}
+	flags.Var(&tables, "table", "Table as variant,decks,min bet,max bet, repeat for more tables.  Default is three tables")
+	cashPtr := flags.Int("cash", 100, "Bankroll each new player starts with.  Default is 100")
+	reconnectPtr := flags.Int("reconnectWait", 30, "Seconds the table waits for a dropped player before sitting them out.  Default is 30")
+	decisionTimeoutPtr := flags.Int("decisionTimeout", 30, "Seconds a player has for each decision.  Default is 30")
+	timeBankPtr := flags.Int("timeBank", 30, "Extra seconds each player can draw on once a decision's time is up.  Default is 30")
+	timeoutPlayPtr := flags.String("timeoutPlay", "stand", "Play for a player out of time (stand or basic).  Default is stand")
+
+	flags.Parse(args)
+
+	timeoutOpts, err := TimeoutOptions(*decisionTimeoutPtr, *timeBankPtr, *timeoutPlayPtr)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	if len(tables) == 0 {
+		tables = commandList{"classic,6,1,100", "freebet,6,5,500", "doubleexposure,2,10,1000"}
+	}
+
+	rules := []TableRules{}
+	for _, spec := range tables {
+		r, err := ParseTableRules(spec)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		rules = append(rules, r)
+	}
+
+	listener, err := net.Listen("tcp", *addrPtr)
+	if err != nil {
+		fmt.Println(fmt.Errorf("cannot listen on %s, %s", *addrPtr, err))
+		os.Exit(1)
+	}
+
+	l, err := NewLobby(listener, rules, timeoutOpts...)
+	if err != nil {
+		fmt.Println(fmt.Errorf("cannot create lobby, %s", err))
+		os.Exit(1)
+	}
+	l.StartingCash = *cashPtr
+	for _, t := range l.Tables {
+		t.Server.ReconnectWait = time.Duration(*reconnectPtr) * time.Second
+	}
+
+	fmt.Printf("Serving the blackjack lobby on %s\n", listener.Addr())
+	err = l.Run()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+// ParseTableRules reads a table given as variant,decks,min bet,max bet.
+// Fields left off take the defaults
+func ParseTableRules(spec string) (TableRules, error) {
+
+	rules := TableRules{}
+	fields := strings.Split(spec, ",")
+	if len(fields) > 4 {
+		return rules, fmt.Errorf("table %q has too many fields, want variant,decks,min bet,max bet", spec)
+	}
+
+	numbers := []*int{&rules.Decks, &rules.MinBet, &rules.MaxBet}
+	for i, field := range fields {
+		field = strings.TrimSpace(field)
+		if i == 0 {
+			rules.Variant = field
+			continue
+		}
+		n, err := strconv.Atoi(field)
+		if err != nil {
+			return rules, fmt.Errorf("table %q, %s is not a number", spec, field)
+		}
+		*numbers[i-1] = n
+	}
+
+	return rules, nil
+}
+
+// TimeoutOptions are the options for a decision timeout and time bank
+// given in seconds and the play for a player out of time
+func TimeoutOptions(timeout, bank int, play string) ([]Option, error) {
+
+	timeoutPlay, ok := TimeoutPlayInputMap[strings.ToLower(play)]
+	if !ok {
+		return nil, fmt.Errorf("unknown timeout play, %s", play)
+	}
+
+	return []Option{
+		WithDecisionTimeout(time.Duration(timeout) * time.Second),
+		WithTimeBank(time.Duration(bank) * time.Second),
+		WithTimeoutPlay(timeoutPlay),
+	}, nil
+}
+
+// RunAPI serves tables over HTTP for browsers and bots
+func RunAPI(args []string) {
+
+	flags := flag.NewFlagSet("api", flag.ExitOnError)
+	flags.Usage = help
+
+	addrPtr := flags.String("addr", ":8080", "Address to serve the API on.  Default is :8080")
+
+	flags.Parse(args)
+
+	fmt.Printf("Serving the blackjack API on %s\n", *addrPtr)
+	err := http.ListenAndServe(*addrPtr, NewAPI())
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+func NewBlackjackGameWithArgs(humanPlayers, aiPlayers, deckCount int, opts ...Option) (*Game, error) {
+
+	opts = append([]Option{
 		WithNumberOfHumanPlayers(humanPlayers),
 		WithNumberOfAiPlayers(aiPlayers),
 		WithDeckCount(deckCount),
-	)
+	}, opts...)
+
+	g, err := NewBlackjackGame(opts...)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create game, %s", err)
 	}
@@ -65,65 +739,267 @@ func (g *Game) AddBlackjackPlayers() {
 
 	for i := 0; i < g.NumberHumanPlayers; i++ {
 		player := NewHumanPlayer(g.output, g.input, i)
-		g.AddPlayer(player)
+		g.LoadProfile(player)
+		err := g.ChooseSeat(player)
+		if err != nil {
+			fmt.Fprintln(g.output, err)
+			return
+		}
 	}
 
 	for i := 0; i < g.NumberAiPlayers; i++ {
 		player := NewAiPlayer(g.output, g.input, i)
-		g.AddPlayer(player)
+		err := g.AddPlayer(player)
+		if err != nil {
+			fmt.Fprintln(g.output, err)
+			return
+		}
 	}
 
+	g.AddBots()
 }
 
-func (g *Game) Betting() error {
+// AddBots starts a bot player for each bot command
+func (g *Game) AddBots() {
 
-	g.SetStage(StageBetting)
+	for i, command := range g.BotCommands {
+		player, err := g.NewBotPlayer(command, i)
+		if err != nil {
+			fmt.Fprintln(g.output, err)
+			continue
+		}
+		err = g.AddPlayer(player)
+		if err != nil {
+			fmt.Fprintln(g.output, err)
+			return
+		}
+	}
+}
 
-	var err error
+// ChooseSeat asks a human player which empty seat to take
+func (g *Game) ChooseSeat(player *Player) error {
 
-	for _, player := range g.Players {
-		g.ActivePlayer = player
+	seats := g.EmptySeats()
+	if g.SeatCount == 0 || len(seats) == 0 {
+		return g.AddPlayer(player)
+	}
+
+	output := g.PlayerOutput(player)
+	reader := BufferedReader(g.PlayerInput(player))
+
+	for {
+		str := []string{}
+		for _, seat := range seats {
+			str = append(str, strconv.Itoa(seat))
+		}
+		fmt.Fprintf(output, "%s choose a seat (%s) [%d]: ", player.Name, strings.Join(str, ", "), seats[0])
+
+		answer, _ := reader.ReadString('\n')
+		answer = strings.Replace(answer, "\n", "", -1)
+		if answer == "" {
+			return g.SitPlayer(player, seats[0])
+		}
+
+		seat, err := strconv.Atoi(answer)
+		if err == nil {
+			err = g.SitPlayer(player, seat)
+			if err == nil {
+				return nil
+			}
+		}
+		fmt.Fprintln(g.output, err)
+	}
+
+}
+
+// PlayRound runs one round on the engine, asking each player's bet,
+// switch and decide funcs for the input it needs
+func (g *Game) PlayRound() error {
+	return g.PlayRoundContext(context.Background())
+}
+
+// PlayRoundContext is PlayRound giving up on the round once ctx is
+// done.  A decision being waited on when ctx is done is answered with
+// the timeout play
+func (g *Game) PlayRoundContext(ctx context.Context) error {
+
+	err := g.play(ctx, g.StartRound())
+	if err != nil {
+		return err
+	}
+
+	if g.Stage == StageOutcome {
+		g.RenderOutcome(g.output)
+	}
+	g.CountCheck()
+	g.Players = g.RemoveQuitPlayers()
+
+	return nil
+}
 
-		err = player.Bet(g)
+// play asks for each decision in turn until the round is over, or the
+// stage being stepped through is done
+func (g *Game) play(ctx context.Context, decision Decision) error {
 
+	var turn *Player
+	var hand *Hand
+
+	for decision.Type != DecisionRoundOver && decision.Type != DecisionNone {
+		err := ctx.Err()
 		if err != nil {
-			return fmt.Errorf("unable to place bet for player: %s", player.Name)
+			return err
+		}
+
+		if decision.Type == DecisionAction {
+			if decision.Player != turn {
+				turn = decision.Player
+				g.RenderTurn(turn)
+			}
+			if decision.Player.Hands[decision.HandIndex] != hand {
+				hand = decision.Player.Hands[decision.HandIndex]
+				RenderPlayerAndDealerCards(g.output, g.input, g.Players, g.Dealer, g.Stage)
+			}
+
+			// a hand dealt with its action already set plays it without asking
+			if hand.Action != None {
+				decision, err = g.ApplyAction(decision.Player, hand.Action)
+				if err != nil {
+					decision, err = g.ApplyAction(decision.Player, FallbackAction(hand.Action))
+				}
+				if err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		decision, err = g.AskContext(ctx, decision)
+		if err != nil {
+			return err
 		}
 	}
+
 	return nil
 }
 
-func (g *Game) OpeningDeal() {
+// playStage plays a single stage of the round on the engine, for the
+// deprecated stage by stage round
+func (g *Game) playStage(s step) error {
+
+	g.step = s
+	defer func() {
+		g.step = step{}
+	}()
+
+	g.cursor = cursor{}
+	if s.player != nil {
+		found := false
+		for i, player := range g.Players {
+			if player == s.player {
+				g.cursor.player = i
+				found = true
+			}
+		}
+		if !found {
+			return fmt.Errorf("%s is not at the table", s.player.Name)
+		}
+	}
 
-	g.SetStage(StageOpeningDeal)
-	RenderStageMessage(g.output, g.StageMessage)
+	return g.play(context.Background(), g.advance())
+}
 
-	for i := 0; i < 2; i++ {
-		for _, player := range g.Players {
-			g.SetActivePlayer(player)
-			card := g.Deal(g.output)
-			player.Hands[0].Cards = append(player.Hands[0].Cards, card)
+// Ask gets the answer to the decision from the player and hands it to
+// the engine
+func (g *Game) Ask(decision Decision) (Decision, error) {
+	return g.AskContext(context.Background(), decision)
+}
 
-			player.Message = player.Name + " is dealt the " + card.Render() + "\n"
+// AskContext is Ask with a human player's decision timed when there is
+// a decision timeout.  The decision is also timed out once ctx is done
+func (g *Game) AskContext(ctx context.Context, decision Decision) (Decision, error) {
 
-			RenderPlayerMessage(g.output, player)
-			time.Sleep(750 * time.Millisecond)
+	player := decision.Player
 
+	clock := g.startClock(ctx, decision)
+	if clock != nil {
+		defer clock.stop()
+	}
+
+	switch decision.Type {
+	case DecisionBet:
+		err := player.Bet(g)
+		if err != nil {
+			return decision, fmt.Errorf("unable to place bet for player: %s", player.Name)
 		}
-		card := g.Deal(g.output)
-		g.Dealer.Hands[0].Cards = append(g.Dealer.Hands[0].Cards, card)
+		if player.Action == ActionQuit {
+			return g.ApplyAction(player, ActionQuit)
+		}
+		return g.ConfirmBets(player)
 
-		if i == 0 {
-			g.Dealer.Message = "Dealer is dealt a [??]\n" + "\n"
-		} else {
-			g.Dealer.Message = "Dealer is dealt a " + card.Render() + "\n"
+	case DecisionSwitch:
+		action := ActionKeep
+		if player.Switch != nil {
+			err := player.Switch(g)
+			if err != nil {
+				return decision, fmt.Errorf("unable to switch for player: %s", player.Name)
+			}
+			action = player.Action
 		}
-		RenderPlayerMessage(g.output, g.Dealer)
-		time.Sleep(750 * time.Millisecond)
+		return g.ApplyAction(player, action)
+
+	case DecisionAction:
+		player.Message = player.Hands[decision.HandIndex].HandString(player.Name, player.Theme)
+		RenderPlayerMessage(g.output, player)
 
+		action := player.Decide(g.PlayerOutput(player), g.PlayerInput(player), player, decision.DealerCard, decision.HandIndex, g.CountFor(player), g.Stage)
+		next, err := g.ApplyAction(player, action)
+		if err != nil {
+			fmt.Fprintln(g.output, err)
+			return g.ApplyAction(player, FallbackAction(action))
+		}
+		return next, nil
 	}
 
-	RenderPlayerAndDealerCards(g.output, g.input, g.Players, g.Dealer, g.Stage)
+	return decision, fmt.Errorf("cannot ask for a %s decision", decision.Type)
+}
+
+// FallbackAction replaces an action the hand is not allowed to take
+func FallbackAction(action Action) Action {
+	if action == ActionDoubleDown || action == ActionSplit {
+		return ActionHit
+	}
+	return ActionStand
+}
+
+// Betting asks every player for their bets on the engine.
+//
+// Deprecated: PlayRound plays the whole round
+func (g *Game) Betting() error {
+
+	g.SetStage(StageBetting)
+	return g.playStage(step{stage: StageBetting})
+}
+
+// Switching asks every player in Switch whether to switch their cards.
+//
+// Deprecated: PlayRound plays the whole round
+func (g *Game) Switching() error {
+
+	if g.Variant != VariantSwitch {
+		return nil
+	}
+
+	g.SetStage(StageSwitching)
+	return g.playStage(step{stage: StageSwitching})
+}
+
+// OpeningDeal deals two cards to every hand and the dealer.
+//
+// Deprecated: PlayRound plays the whole round
+func (g *Game) OpeningDeal() {
+
+	g.SetStage(StageOpeningDeal)
+	g.dealOpeningCards()
 }
 
 func NewHumanPlayer(output io.Writer, input io.Reader, index int) *Player {
@@ -132,7 +1008,7 @@ func NewHumanPlayer(output io.Writer, input io.Reader, index int) *Player {
 
 	fmt.Fprintf(output, "%s enter your name [%s]: ", defaultName, defaultName)
 
-	reader := bufio.NewReader(input)
+	reader := BufferedReader(input)
 	name, _ := reader.ReadString('\n')
 	name = strings.Replace(name, "\n", "", -1)
 
@@ -140,20 +1016,33 @@ func NewHumanPlayer(output io.Writer, input io.Reader, index int) *Player {
 		name = defaultName
 	}
 
-	player := &Player{
+	fmt.Fprintf(output, "%s enter number of spots to play (1-%d) [1]: ", name, MaxSpots)
+	answer, _ := reader.ReadString('\n')
+	answer = strings.Replace(answer, "\n", "", -1)
+	spots, err := strconv.Atoi(answer)
+	if err != nil || spots < 1 || spots > MaxSpots {
+		spots = 1
+	}
+
+	return HumanPlayer(name, spots)
 
+}
+
+// HumanPlayer is a new human player with the starting bankroll
+func HumanPlayer(name string, spots int) *Player {
+	return &Player{
 		Name:       name,
+		Type:       PlayerTypeHuman,
 		Decide:     HumanAction,
 		Bet:        HumanBet,
+		Switch:     HumanSwitch,
 		CurrentBet: 1,
+		Spots:      spots,
 		Cash:       100,
 		Hands: []*Hand{
 			{Id: 1},
 		},
 	}
-
-	return player
-
 }
 
 func NewAiPlayer(output io.Writer, input io.Reader, index int) *Player {
@@ -163,7 +1052,7 @@ func NewAiPlayer(output io.Writer, input io.Reader, index int) *Player {
 
 	defaultName := "AiPlayer" + strconv.Itoa(index+1)
 
-	reader := bufio.NewReader(input)
+	reader := BufferedReader(input)
 
 	fmt.Fprintf(output, "%s enter your name [%s]: ", defaultName, defaultName)
 	name, _ = reader.ReadString('\n')
@@ -173,8 +1062,8 @@ func NewAiPlayer(output io.Writer, input io.Reader, index int) *Player {
 		name = defaultName
 	}
 
-	for strings.ToLower(playerTypeInput) != "b" && strings.ToLower(playerTypeInput) != "s" && strings.ToLower(playerTypeInput) != "x" {
-		fmt.Fprintf(output, "Select AI Type (B)asic Strategy, (S)tandOnly or (X)custom [B]: ")
+	for strings.ToLower(playerTypeInput) != "b" && strings.ToLower(playerTypeInput) != "s" && strings.ToLower(playerTypeInput) != "f" && strings.ToLower(playerTypeInput) != "e" && strings.ToLower(playerTypeInput) != "x" {
+		fmt.Fprintf(output, "Select AI Type (B)asic Strategy, (S)tandOnly, (F)ree Bet, Double (E)xposure or (X)custom [B]: ")
 		//fmt.Fscanln(input, &playerTypeInput)
 		playerTypeInput, _ = reader.ReadString('\n')
 		playerTypeInput = strings.Replace(playerTypeInput, "\n", "", -1)
@@ -187,6 +1076,7 @@ func NewAiPlayer(output io.Writer, input io.Reader, index int) *Player {
 	playerTypeInputValue := PlayerTypeInputMap[strings.ToLower(playerTypeInput)]
 	playerType := PlayerTypeMap[playerTypeInputValue]
 	playerTypeBet := PlayerTypeBetMap[playerTypeInputValue]
+	playerTypeSwitch := PlayerTypeSwitchMap[playerTypeInputValue]
 
 	aiHands := 0
 	var err error
@@ -203,8 +1093,10 @@ func NewAiPlayer(output io.Writer, input io.Reader, index int) *Player {
 	player := &Player{
 
 		Name:           name,
+		Type:           playerTypeInputValue,
 		Decide:         playerType,
 		Bet:            playerTypeBet,
+		Switch:         playerTypeSwitch,
 		AiRoundsToPlay: aiHands,
 		Cash:           100,
 		Hands: []*Hand{
@@ -216,132 +1108,143 @@ func NewAiPlayer(output io.Writer, input io.Reader, index int) *Player {
 
 }
 
+// Deciding plays every player's hands on the engine.
+//
+// Deprecated: PlayRound plays the whole round
 func (g *Game) Deciding() error {
 
 	g.SetStage(StageDeciding)
+	return g.playStage(step{stage: StageDeciding})
+}
 
-	var err error
+// PlayHand plays the player's hands on the engine.
+//
+// Deprecated: PlayRound plays the whole round
+func (g *Game) PlayHand(player *Player) error {
 
-	for _, player := range g.Players {
+	if g.Stage != StageDeciding {
+		g.SetStage(StageDeciding)
+	}
+	return g.playStage(step{stage: StageDeciding, player: player})
+}
 
-		g.StageMessage = strings.ToUpper(player.Name) + " MAKE YOUR CHOICE"
-		RenderStageMessage(g.output, g.StageMessage)
+// DealerPlay turns over the hole card and draws for the dealer.
+//
+// Deprecated: PlayRound plays the whole round
+func (g *Game) DealerPlay() {
 
-		g.ActivePlayer = player
+	g.SetStage(StageDealerPlay)
+	g.dealerDraw()
+}
 
-		err = g.PlayHand(player)
-		if err != nil {
-			return err
-		}
+// Outcome settles every hand and ends the round.
+//
+// Deprecated: PlayRound plays the whole round
+func (g *Game) Outcome(output io.Writer) {
 
-		if err != nil {
-			return fmt.Errorf("unable to place bet for player: %s", player.Name)
-		}
+	g.SetStage(StageOutcome)
+	g.settle()
+	g.RenderOutcome(output)
+
+	g.Players = g.RemoveQuitPlayers()
+	g.endRound()
+}
+
+// RenderOutcome shows the settled table and each player's results
+func (g *Game) RenderOutcome(output io.Writer) {
+
+	RenderPlayerAndDealerCards(g.output, g.input, g.Players, g.Dealer, g.Stage)
+
+	for _, player := range g.Players {
+		player.OutcomeReport(output)
 	}
-	return nil
 }
 
-func (g *Game) PlayHand(player *Player) error {
-	for index, hand := range player.Hands {
-		if hand.Score() == 21 {
-			hand.Outcome = OutcomeBlackjack
-		}
+// RenderTurn announces the player who is about to play
+func (g *Game) RenderTurn(player *Player) {
 
-		err := RenderPlayerAndDealerCards(g.output, g.input, g.Players, g.Dealer, g.Stage)
-		if err != nil {
-			return err
-		}
+	g.StageMessage = strings.ToUpper(player.Name) + " MAKE YOUR CHOICE"
+	RenderStageMessage(g.output, g.StageMessage)
+}
 
-		for hand.ChooseAction() {
-			if hand.Action == None {
-				player.Message = hand.HandString(player.Name)
-				RenderPlayerMessage(g.output, player)
+// RenderEvent is the terminal view of the game.  It is subscribed to
+// the event stream unless rendering is turned off
+func (g *Game) RenderEvent(e Event) {
+
+	switch e.Type {
+	case EventShoeShuffled:
+		RenderStageMessage(g.output, "NEW DECK INCOMING")
+
+	case EventStageChanged:
+		switch e.Stage {
+		case StageBetting:
+			RenderSeats(g.output, g)
+		case StageSwitching:
+			RenderPlayerAndDealerCards(g.output, g.input, g.Players, g.Dealer, e.Stage)
+			RenderStageMessage(g.output, e.Stage.Message())
+		case StageOpeningDeal, StageDealerPlay, StageOutcome:
+			RenderStageMessage(g.output, e.Stage.Message())
+		}
 
-				hand.Action = player.Decide(g.output, g.input, player, g.Dealer.Hands[0].Cards[1], index, g.CardCounter, g.Stage)
-			}
-			if hand.Action == ActionHit {
-				card := g.Deal(g.output)
-				player.Message = player.Name + " is dealt the [" + card.String() + "]\n\n"
-				hand.Hit(g.output, card, player.Name)
-				RenderPlayerMessage(g.output, player)
-			} else if hand.Action == ActionDoubleDown {
-				player.Cash -= hand.Bet
-				card := g.Deal(g.output)
-				player.Message = player.Name + " is dealt [??]\n\n"
-				hand.DoubleDown(g.output, card, player.Name)
-				RenderPlayerMessage(g.output, player)
-			} else if hand.Action == ActionSplit {
-				card1 := g.Deal(g.output)
-				card2 := g.Deal(g.output)
-				player.Split(g.output, card1, card2, index)
-				err = g.PlayHand(player)
-				if err != nil {
-					return err
-				}
+	case EventCardDealt:
+		g.renderCardDealt(e)
 
+	case EventActionTaken:
+		if e.Stage == StageSwitching {
+			if e.Action == ActionSwitch {
+				fmt.Fprint(g.output, e.Player+" switches cards\n")
+			} else {
+				fmt.Fprint(g.output, e.Player+" keeps cards\n")
 			}
 		}
 	}
-	return nil
-
 }
 
-func (g *Game) DealerPlay() {
+func (g *Game) renderCardDealt(e Event) {
 
-	g.SetStage(StageDealerPlay)
-	RenderStageMessage(g.output, g.StageMessage)
-	dealerOk := g.IsDealerDraw()
-
-	if dealerOk {
+	player := g.eventPlayer(e)
+	if player == nil {
+		return
+	}
 
-		for g.Dealer.Hands[0].Score() <= 16 || (g.Dealer.Hands[0].Score() == 17 && g.Dealer.Hands[0].MinScore() != 17) {
-			card := g.Deal(g.output)
-			g.Dealer.Hands[0].Cards = append(g.Dealer.Hands[0].Cards, card)
-			g.Dealer.Message = "Dealer is dealt a " + card.Render() + "\n"
-			RenderPlayerMessage(g.output, g.Dealer)
-			time.Sleep(2 * time.Second)
+	switch e.Stage {
+	case StageOpeningDeal:
+		if player == g.Dealer && e.FaceDown {
+			fmt.Fprint(g.output, "Dealer is dealt a [??]\n"+"\n")
+		} else if player == g.Dealer {
+			fmt.Fprint(g.output, "Dealer is dealt a "+g.Theme.Card(e.Card)+"\n")
+		} else if len(player.Hands) > 1 {
+			fmt.Fprint(g.output, player.Name+" hand #"+strconv.Itoa(e.HandId)+" is dealt the "+g.Theme.Card(e.Card)+"\n")
+		} else {
+			fmt.Fprint(g.output, player.Name+" is dealt the "+g.Theme.Card(e.Card)+"\n")
 		}
-		g.Dealer.Hands[0].Action = ActionStand
-		RenderPlayerAndDealerCards(g.output, g.input, g.Players, g.Dealer, g.Stage)
+		g.pause(e.Stage)
+	case StageDeciding:
+		if e.FaceDown {
+			fmt.Fprint(g.output, player.Name+" is dealt [??]\n\n")
+		} else {
+			fmt.Fprint(g.output, player.Name+" is dealt the "+g.Theme.Card(e.Card)+"\n\n")
+		}
+	case StageDealerPlay:
+		fmt.Fprint(g.output, "Dealer is dealt a "+g.Theme.Card(e.Card)+"\n")
+		g.pause(e.Stage)
 	}
 }
 
-func (g *Game) Outcome(output io.Writer) {
+// eventPlayer finds the player or dealer an event is about
+func (g *Game) eventPlayer(e Event) *Player {
 
-	g.SetStage(StageOutcome)
-	RenderStageMessage(g.output, g.StageMessage)
-
-	var outcome Outcome
 	for _, player := range g.Players {
-		for _, hand := range player.Hands {
-			if hand.Outcome == OutcomeBlackjack || hand.Outcome == OutcomeBust {
-				outcome = hand.Outcome
-			} else if g.Dealer.Hands[0].Score() > 21 {
-				outcome = OutcomeWin
-			} else if hand.Score() > g.Dealer.Hands[0].Score() {
-				outcome = OutcomeWin
-			} else if hand.Score() < g.Dealer.Hands[0].Score() {
-				outcome = OutcomeLose
-			} else if hand.Score() == g.Dealer.Hands[0].Score() {
-				outcome = OutcomeTie
-			}
-
-			hand.Outcome = outcome
-
+		if player.Name == e.Player && player.Seat == e.Seat {
+			return player
 		}
+	}
 
-		RenderPlayerAndDealerCards(g.output, g.input, g.Players, g.Dealer, g.Stage)
-
-		player.SetWinLoseTie()
-
-		player.Payout()
-
-		player.Broke()
-
-		player.OutcomeReport(output)
+	if e.Player == g.Dealer.Name {
+		return g.Dealer
 	}
 
-	g.RemoveQuitPlayers()
+	return nil
 }
 
 func RenderGameCli(output io.Writer, input io.Reader, g *Game) error {
@@ -366,10 +1269,10 @@ func RenderPlayerAndDealerCards(output io.Writer, input io.Reader, players []*Pl
 
 		for _, player := range players {
 			for _, hand := range player.Hands {
-				if player.HandIndex == 0 {
-					fmt.Fprint(output, hand.HandString(player.Name))
+				if len(player.Hands) == 1 {
+					fmt.Fprint(output, hand.HandString(player.Name, player.Theme))
 				} else {
-					fmt.Fprint(output, hand.HandStringMulti(player.Name))
+					fmt.Fprint(output, hand.HandStringMulti(player.Name, player.Theme))
 				}
 
 			}
@@ -380,22 +1283,22 @@ func RenderPlayerAndDealerCards(output io.Writer, input io.Reader, players []*Pl
 		}
 
 		for _, h := range dealer.Hands {
-			fmt.Fprint(output, h.DealerHandString())
+			fmt.Fprint(output, h.DealerHandString(dealer.Variant == VariantDoubleExposure, dealer.Theme))
 		}
 
 	} else if stage == StageOutcome {
 		for _, player := range players {
 			for _, hand := range player.Hands {
-				if player.HandIndex == 0 {
-					fmt.Fprint(output, ReportMap[hand.Outcome]+" "+hand.HandString(player.Name))
+				if len(player.Hands) == 1 {
+					fmt.Fprint(output, ReportMap[hand.Outcome]+" "+hand.HandString(player.Name, player.Theme))
 				} else {
-					fmt.Fprint(output, ReportMap[hand.Outcome]+" "+hand.HandStringMulti(player.Name))
+					fmt.Fprint(output, ReportMap[hand.Outcome]+" "+hand.HandStringMulti(player.Name, player.Theme))
 				}
 
 			}
 
 			for _, h := range dealer.Hands {
-				fmt.Fprint(output, h.HandString(dealer.Name))
+				fmt.Fprint(output, h.HandString(dealer.Name, dealer.Theme))
 			}
 		}
 
@@ -404,6 +1307,28 @@ func RenderPlayerAndDealerCards(output io.Writer, input io.Reader, players []*Pl
 	return nil
 }
 
+// RenderSeats shows who is sitting where, from first base to third base
+func RenderSeats(output io.Writer, g *Game) {
+
+	if g.SeatCount == 0 {
+		return
+	}
+
+	fmt.Fprint(output, "\n")
+	for seat := 1; seat <= g.SeatCount; seat++ {
+		name := "empty"
+		player := g.SeatedPlayer(seat)
+		if player != nil {
+			name = player.Name
+			if player.Waiting {
+				name += " (waiting for next shoe)"
+			}
+		}
+		fmt.Fprintf(output, "Seat %d: %s\n", seat, name)
+	}
+	fmt.Fprint(output, "\n")
+}
+
 func RenderPlayerMessage(output io.Writer, player *Player) {
 
 	fmt.Fprint(output, player.Message)
@@ -417,7 +1342,7 @@ func RenderPlayerInput(output io.Writer, input io.Reader, player *Player, stage
 
 	for !ok {
 
-		reader := bufio.NewReader(input)
+		reader := BufferedReader(input)
 		answer, _ := reader.ReadString('\n')
 		answer = strings.Replace(answer, "\n", "", -1)
 
@@ -427,8 +1352,7 @@ func RenderPlayerInput(output io.Writer, input io.Reader, player *Player, stage
 		}
 
 		if answer == "?" && stage == StageDeciding {
-			index := 0
-			action := GetHint(output, input, player, dealerCard, index, c, stage)
+			action := GetHint(output, input, player, dealerCard, player.HandIndex, c, stage)
 			hint := "The suggested action is to " + action.String() + "\n"
 			fmt.Fprintln(output, hint)
 		}
@@ -491,15 +1415,25 @@ func (p *Player) SetAction(answer string) error {
 			return fmt.Errorf("unable to set bet amount, %s", err)
 		}
 
-		p.CurrentBet = bet
-		p.Cash -= bet
-		p.Hands[p.HandIndex].Bet += bet
+		// a bet of 0 leaves the spot empty
+		if bet > 0 {
+			p.CurrentBet = bet
+			p.PlaceBet(p.HandIndex, bet)
+		}
 	case DialogHitOrStand:
 		p.Action = ActionMap[strings.ToLower(answer)]
 	case DialogHitDoubleStand:
 		p.Action = ActionMap[strings.ToLower(answer)]
 	case DialogHitSplitDoubleStand:
 		p.Action = ActionMap[strings.ToLower(answer)]
+	case DialogHitSplitStand:
+		p.Action = ActionMap[strings.ToLower(answer)]
+	case DialogSwitchOrKeep:
+		if answer == "" {
+			p.Action = ActionKeep
+		} else {
+			p.Action = ActionMap[strings.ToLower(answer)]
+		}
 
 	default:
 		return fmt.Errorf("missing Dialog in switch, %s", p.Dialog.String())
@@ -521,10 +1455,13 @@ func IsInputValid(answer string, player *Player) (bool, error) {
 
 		bet, err := strconv.Atoi(answer)
 		if answer == "" {
-			ok = true
-		} else if err != nil {
+			bet = player.CurrentBet
+		}
+		if err != nil && answer != "" {
 			ok = false
-		} else if bet < 1 || bet > player.Cash {
+		} else if bet == 0 && player.SpotCount() > 1 {
+			ok = true
+		} else if bet < player.MinBet() || bet > player.MaxBet() {
 			ok = false
 		} else {
 			ok = true
@@ -541,6 +1478,14 @@ func IsInputValid(answer string, player *Player) (bool, error) {
 		if strings.ToLower(answer) == "h" || strings.ToLower(answer) == "s" || strings.ToLower(answer) == "d" || strings.ToLower(answer) == "p" {
 			ok = true
 		}
+	case DialogHitSplitStand:
+		if strings.ToLower(answer) == "h" || strings.ToLower(answer) == "s" || strings.ToLower(answer) == "p" {
+			ok = true
+		}
+	case DialogSwitchOrKeep:
+		if strings.ToLower(answer) == "w" || strings.ToLower(answer) == "k" || answer == "" {
+			ok = true
+		}
 	default:
 		return ok, fmt.Errorf("missing Dialog in switch, %s", player.Dialog.String())
 	}
@@ -554,8 +1499,129 @@ func help() {
 	  humanPlayers     Number of human players.  Default is 1
 	  aiPlayers        Number of Ai players.  Default is 0
 	  deckCount        Number of decks in shoe.  Default is 6
+	  variant          Rules variant (classic, freebet, doubleexposure or switch).  Default is classic
+	  blackjackTiePush Tied blackjacks push in Double Exposure.  Default is false
+	  seats            Number of seats at the table.  Default is 7
+	  noMidShoeEntry   New players wait for the next shoe.  Default is false
+	  seed             Seed for shuffling the shoe.  Default is random
+	  handHistory      File to append the hand history to.  Default is none
+	  saveFile         File the game is saved to between rounds.  Default is blackjack.save
+	  resume           Resume the game in the save file.  A new game is not started over one that is saved.  Default is false
+	  profiles         File human player profiles are kept in.  Default is blackjack.profiles
+	  showProfile      Show the lifetime report of a player profile and exit
+	  strategy         Strategy human decisions are checked against (basic or count).  Default is basic
+	  countSystem      Counting system (hilo, hiopt1 or omega2).  Default is hilo
+	  countPractice    Hide the count and check the players' own count.  Default is false
+	  countCheat       Still show the count with c in count practice.  Default is false
+	  bot              Command that runs a bot player, repeat for more bots.  Default is none
+	  botTimeout       Milliseconds a bot has to answer.  Default is 1000
+	  botRounds        Number of rounds each bot plays.  Default is until it quits
+	  botLog           File late and illegal bot replies are logged to.  Default is the table
+	  decisionTimeout  Seconds a human player has for each decision.  Default is no limit
+	  timeBank         Extra seconds each human player can draw on once a decision's time is up.  Default is 30
+	  timeoutPlay      Play for a human player out of time (stand or basic).  Default is stand
+	  fullScreen       Draw the table full screen, redrawn in place.  Default is false
+	  showCount        Show the running count on the full screen table.  Default is false
+	  theme            How cards are drawn (default, color, mono, ascii or art).  Default is color on a terminal unless NO_COLOR is set
+	  pace             How quickly cards are dealt (normal, fast, instant or step).  Default is normal with human players and instant without
 	
+	Replay parameters:
+	  file             Hand history file to replay
+	  seed             Seed the session was dealt with.  Default is the seed in the file
+	  round            Last round to replay.  Default is every round
+	  step             Wait for enter after each round.  Default is false
+	  pace             How quickly cards are dealt (normal, fast, instant or step).  Default is instant
+
+	Drill parameters:
+	  hands            Number of hands to drill.  Default is 20
+	  weighted         Favour difficult hands and the ones answered wrong.  Default is false
+	  strategy         Strategy the answers are checked against (basic or count).  Default is basic
+	  seed             Seed for dealing the hands.  Default is random
+
+	Count drill parameters:
+	  mode             Drill to run (flash, countdown or convert).  Default is flash
+	  rounds           Number of rounds to drill.  Default is 5
+	  cards            Number of cards flashed each round.  Default is 10
+	  group            Number of cards in each flash.  Default is 1
+	  speed            Milliseconds each flash is shown.  Default is 1000
+	  progressive      Speed up after each round counted right.  Default is false
+	  system           Counting system (hilo, hiopt1 or omega2).  Default is hilo
+	  deckCount        Number of decks in shoe.  Default is 6
+	  seed             Seed for shuffling the shoe.  Default is random
+
+	Tournament parameters:
+	  humanPlayers     Number of human players.  Default is 1
+	  aiPlayers        Number of AI players.  Default is 7
+	  chips            Chips every player starts each round with.  Default is 1000
+	  hands            Number of hands in each round.  Default is 10
+	  tableSize        Most players at a table.  Default is 6
+	  advance          Number of players who advance from each table.  Default is 2
+	  minBet           Table minimum bet.  Default is 10
+	  maxBet           Table maximum bet.  Default is 500
+	  secretBets       Keep bets on the final hand of each round secret.  Default is false
+	  deckCount        Number of decks in shoe.  Default is 6
+	  variant          Rules variant (classic, freebet, doubleexposure or switch).  Default is classic
+	  seed             Seed for shuffling the shoe.  Default is random
+	  theme            How cards are drawn (default, color, mono, ascii or art).  Default is color on a terminal unless NO_COLOR is set
+	  pace             How quickly cards are dealt (normal, fast, instant or step).  Default is normal with human players and instant without
+
+	Serve parameters:
+	  addr             Address to serve the table on.  Default is :4000
+	  deckCount        Number of decks in shoe.  Default is 6
+	  variant          Rules variant (classic, freebet, doubleexposure or switch).  Default is classic
+	  seats            Number of seats at the table.  Default is 7
+	  seed             Seed for shuffling the shoe.  Default is random
+	  reconnectWait    Seconds the table waits for a dropped player before sitting them out.  Default is 30
+	  decisionTimeout  Seconds a player has for each decision.  Default is 30
+	  timeBank         Extra seconds each player can draw on once a decision's time is up.  Default is 30
+	  timeoutPlay      Play for a player out of time (stand or basic).  Default is stand
+
+	Connect parameters:
+	  addr             Address of the table.  Default is localhost:4000
+	  watch            Watch the table without a seat.  Default is false
+	  delay            Milliseconds each event is held back while watching.  Default is 0
+
+	Lobby parameters:
+	  addr             Address to serve the lobby on.  Default is :4000
+	  table            Table as variant,decks,min bet,max bet, repeat for more tables.  Default is three tables
+	  cash             Bankroll each new player starts with.  Default is 100
+	  reconnectWait    Seconds the table waits for a dropped player before sitting them out.  Default is 30
+	  decisionTimeout  Seconds a player has for each decision.  Default is 30
+	  timeBank         Extra seconds each player can draw on once a decision's time is up.  Default is 30
+	  timeoutPlay      Play for a player out of time (stand or basic).  Default is stand
+
+	API parameters:
+	  addr             Address to serve the API on.  Default is :8080
+
 	Usage:
-	./blackjack -humanPlayers 1 -aiPlayers 1 -deckCount 7
+	./blackjack -humanPlayers 1 -aiPlayers 1 -deckCount 7 -variant freebet
+	./blackjack -seed 42 -handHistory session.jsonl
+	./blackjack -resume
+	./blackjack -showProfile Player1
+	./blackjack -strategy count
+	./blackjack -countPractice
+	./blackjack -decisionTimeout 15 -timeBank 60 -timeoutPlay basic
+	./blackjack -fullScreen -showCount -aiPlayers 3
+	./blackjack -theme art
+	./blackjack -pace step
+	./blackjack -humanPlayers 0 -bot "python3 mybot.py" -bot ./otherbot -botRounds 1000
+	./blackjack replay -file session.jsonl -round 10
+	./blackjack drill -hands 50 -weighted
+	./blackjack count -mode flash -group 2 -speed 800 -progressive
+	./blackjack tournament -aiPlayers 11 -hands 8 -secretBets
+	./blackjack serve -addr :4000
+	./blackjack connect -addr tablehost:4000
+	./blackjack connect -addr tablehost:4000 -watch -delay 1500
+	./blackjack lobby -table classic,6,1,100 -table freebet,2,5,500
+	./blackjack api -addr :8080
 	`)
 }
+
+// BufferedReader reuses the reader when it is already buffered so that
+// input read ahead by one prompt is not lost to the next
+func BufferedReader(input io.Reader) *bufio.Reader {
+	if reader, ok := input.(*bufio.Reader); ok {
+		return reader
+	}
+	return bufio.NewReader(input)
+}
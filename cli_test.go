@@ -3,11 +3,14 @@ package blackjack_test
 import (
 	"blackjack"
 	"bytes"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/mbarley333/cards"
 )
 
 func TestNewBlackjackGameWithArgs(t *testing.T) {
@@ -99,7 +102,7 @@ func TestStageBetting(t *testing.T) {
 
 	got := g.Players[0]
 
-	if !cmp.Equal(want, got, cmpopts.IgnoreFields(blackjack.Player{}, "Bet", "Decide")) {
+	if !cmp.Equal(want, got, cmpopts.IgnoreFields(blackjack.Player{}, "Bet", "Switch", "Decide")) {
 		t.Fatal(cmp.Diff(want, got))
 	}
 
@@ -192,6 +195,7 @@ func TestNewAiPlayer(t *testing.T) {
 
 	want := &blackjack.Player{
 		Name:           "James Bond",
+		Type:           blackjack.PlayerTypeAiBasic,
 		Cash:           100,
 		AiRoundsToPlay: 10,
 		Hands: []*blackjack.Hand{
@@ -201,7 +205,7 @@ func TestNewAiPlayer(t *testing.T) {
 
 	got := blackjack.NewAiPlayer(output, input, index)
 
-	if !cmp.Equal(want, got, cmpopts.IgnoreFields(blackjack.Player{}, "Bet", "Decide")) {
+	if !cmp.Equal(want, got, cmpopts.IgnoreFields(blackjack.Player{}, "Bet", "Switch", "Decide")) {
 		t.Fatal(cmp.Diff(want, got))
 	}
 
@@ -226,3 +230,224 @@ func TestSpacesInNameHumanPlayer(t *testing.T) {
 	}
 
 }
+
+func TestSwitching(t *testing.T) {
+	t.Parallel()
+
+	output := &bytes.Buffer{}
+	input := strings.NewReader("w\n")
+
+	g, err := blackjack.NewBlackjackGame(
+		blackjack.WithOutput(output),
+		blackjack.WithInput(input),
+		blackjack.WithVariant(blackjack.VariantSwitch),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := &blackjack.Player{
+		Name:   "Bumblebee",
+		Cash:   98,
+		Switch: blackjack.HumanSwitch,
+		Hands: []*blackjack.Hand{
+			{
+				Id:    1,
+				Bet:   1,
+				Cards: []cards.Card{{Rank: cards.Ten, Suit: cards.Club}, {Rank: cards.Six, Suit: cards.Club}},
+			},
+			{
+				Id:    2,
+				Bet:   1,
+				Cards: []cards.Card{{Rank: cards.Five, Suit: cards.Heart}, {Rank: cards.Ace, Suit: cards.Heart}},
+			},
+		},
+	}
+	g.AddPlayer(p)
+	g.Dealer.Hands[0].Cards = []cards.Card{{Rank: cards.Nine, Suit: cards.Spade}, {Rank: cards.Seven, Suit: cards.Spade}}
+
+	err = g.Switching()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []cards.Card{{Rank: cards.Ten, Suit: cards.Club}, {Rank: cards.Ace, Suit: cards.Heart}}
+	got := p.Hands[0].Cards
+
+	if !cmp.Equal(want, got) {
+		t.Fatal(cmp.Diff(want, got))
+	}
+}
+
+func TestSwitchBetting(t *testing.T) {
+	t.Parallel()
+
+	g, err := blackjack.NewBlackjackGame(
+		blackjack.WithVariant(blackjack.VariantSwitch),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := &blackjack.Player{
+		Cash:           100,
+		Bet:            blackjack.AiBet,
+		AiRoundsToPlay: 5,
+	}
+	g.AddPlayer(p)
+	g.ResetPlayers()
+
+	err = g.Betting()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []int{1, 1}
+	got := []int{p.Hands[0].Bet, p.Hands[1].Bet}
+
+	if !cmp.Equal(want, got) {
+		t.Fatal(cmp.Diff(want, got))
+	}
+
+	wantCash := 98
+	gotCash := p.Cash
+
+	if wantCash != gotCash {
+		t.Fatalf("want: %d, got: %d", wantCash, gotCash)
+	}
+}
+
+func TestMultipleSpotsBetting(t *testing.T) {
+	t.Parallel()
+
+	output := &bytes.Buffer{}
+	input := strings.NewReader("b\n5\n7\n")
+
+	g, err := blackjack.NewBlackjackGame(
+		blackjack.WithOutput(output),
+		blackjack.WithInput(input),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := &blackjack.Player{
+		Name:   "Jazz",
+		Cash:   100,
+		Spots:  2,
+		Decide: blackjack.HumanAction,
+		Bet:    blackjack.HumanBet,
+	}
+	g.AddPlayer(p)
+	g.ResetPlayers()
+
+	err = g.Betting()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []*blackjack.Hand{
+		{Id: 1, Bet: 5, Spot: 0},
+		{Id: 2, Bet: 7, Spot: 1},
+	}
+	got := p.Hands
+
+	if !cmp.Equal(want, got) {
+		t.Fatal(cmp.Diff(want, got))
+	}
+
+	wantCash := 88
+	gotCash := p.Cash
+
+	if wantCash != gotCash {
+		t.Fatalf("want: %d, got: %d", wantCash, gotCash)
+	}
+}
+
+func TestMultipleSpotsSkipSpot(t *testing.T) {
+	t.Parallel()
+
+	output := &bytes.Buffer{}
+	input := strings.NewReader("b\n0\n7\n")
+
+	g, err := blackjack.NewBlackjackGame(
+		blackjack.WithOutput(output),
+		blackjack.WithInput(input),
+		blackjack.WithRendering(false),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := &blackjack.Player{
+		Name:   "Jazz",
+		Cash:   100,
+		Spots:  2,
+		Decide: blackjack.HumanAction,
+		Bet:    blackjack.HumanBet,
+	}
+	g.AddPlayer(p)
+
+	_, err = g.Ask(g.StartRound())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(output.String(), "$0 to skip") {
+		t.Fatalf("want skipping a spot offered, got: %s", output.String())
+	}
+
+	if len(p.Hands) != 1 || p.Hands[0].Spot != 1 || p.Hands[0].Bet != 7 {
+		t.Fatalf("want only the second spot bet on, got: %v", p.Hands)
+	}
+}
+
+func TestHintForCurrentHand(t *testing.T) {
+	t.Parallel()
+
+	output := &bytes.Buffer{}
+	input := blackjack.BufferedReader(strings.NewReader("?\ns\n"))
+
+	p := &blackjack.Player{
+		Name:   "Wheeljack",
+		Cash:   100,
+		Dialog: blackjack.DialogHitDoubleStand,
+		Hands: []*blackjack.Hand{
+			{Id: 1, Bet: 10, Cards: []cards.Card{{Rank: cards.Ten, Suit: cards.Club}, {Rank: cards.King, Suit: cards.Club}}},
+			{Id: 2, Bet: 10, Cards: []cards.Card{{Rank: cards.Five, Suit: cards.Heart}, {Rank: cards.Six, Suit: cards.Heart}}},
+		},
+		HandIndex: 1,
+	}
+	dealerCard := cards.Card{Rank: cards.Six, Suit: cards.Spade}
+
+	err := blackjack.RenderPlayerInput(output, input, p, blackjack.StageDeciding, blackjack.CardCounter{}, dealerCard)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "The suggested action is to " + blackjack.ActionDoubleDown.String()
+	if !strings.Contains(output.String(), want) {
+		t.Fatalf("want %q for the second hand, got: %s", want, output.String())
+	}
+}
+
+func TestCheckSaveFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "blackjack.save")
+
+	err := blackjack.CheckSaveFile(path)
+	if err != nil {
+		t.Fatalf("want no error without a saved game, got: %s", err)
+	}
+
+	err = os.WriteFile(path, []byte("{}"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = blackjack.CheckSaveFile(path)
+	if err == nil {
+		t.Fatal("want an error starting a new game over a saved one")
+	}
+}
@@ -0,0 +1,202 @@
+package blackjack
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mbarley333/cards"
+)
+
+// CountDrill flashes cards from a shoe and quizzes the running and
+// true count kept by the game's counting system
+type CountDrill struct {
+	Game        *Game
+	Speed       time.Duration
+	MinSpeed    time.Duration
+	Group       int
+	Progressive bool
+	Asked       int
+	Correct     int
+	Pause       func(time.Duration)
+	output      io.Writer
+	input       io.Reader
+	random      *rand.Rand
+}
+
+// NewCountDrill deals from g, which should be created without rendering
+// so that only the drill writes to the terminal
+func NewCountDrill(g *Game, output io.Writer, input io.Reader, random *rand.Rand) *CountDrill {
+
+	d := &CountDrill{
+		Game:     g,
+		Speed:    time.Second,
+		MinSpeed: 100 * time.Millisecond,
+		Group:    1,
+		Pause:    time.Sleep,
+		output:   output,
+		input:    BufferedReader(input),
+		random:   random,
+	}
+
+	g.Subscribe(func(e Event) {
+		if e.Type == EventShoeShuffled {
+			fmt.Fprintln(d.output, "\n*** New shoe, the count starts again at 0 ***")
+		}
+	})
+
+	return d
+}
+
+// flash shows the cards for the drill speed and then wipes them off
+// the line
+func (d *CountDrill) flash(group []cards.Card) {
+
+	builder := strings.Builder{}
+	for _, card := range group {
+		builder.WriteString(card.Render())
+	}
+	fmt.Fprint(d.output, builder.String())
+	d.Pause(d.Speed)
+	fmt.Fprint(d.output, "\r\033[K")
+}
+
+func (d *CountDrill) ask(question string) string {
+
+	fmt.Fprint(d.output, question)
+	answer, _ := BufferedReader(d.input).ReadString('\n')
+	return strings.TrimSpace(answer)
+}
+
+func (d *CountDrill) askInt(question string) (int, bool) {
+	n, err := strconv.Atoi(d.ask(question))
+	return n, err == nil
+}
+
+func (d *CountDrill) askFloat(question string) (float64, bool) {
+	f, err := strconv.ParseFloat(d.ask(question), 64)
+	return f, err == nil
+}
+
+// DiscardDecks is the discard tray as a player would read it, to the
+// nearest half deck
+func (d *CountDrill) DiscardDecks() float64 {
+	return math.Round(float64(d.Game.CardsDealt)/26.0) / 2
+}
+
+func (d *CountDrill) score(correct bool) {
+	d.Asked++
+	if correct {
+		d.Correct++
+	}
+}
+
+// Round flashes count cards in groups and asks for the running count
+// of the shoe and the true count.  A progressive drill speeds up after
+// a round counted right and slows down after a miss
+func (d *CountDrill) Round(count int) bool {
+
+	g := d.Game
+	for dealt := 0; dealt < count; dealt += d.Group {
+		group := []cards.Card{}
+		for i := 0; i < d.Group && dealt+i < count; i++ {
+			group = append(group, g.Deal(io.Discard))
+		}
+		d.flash(group)
+	}
+
+	running, _ := d.askInt("Running count? ")
+	right := running == g.CardCounter.Count
+	d.score(right)
+
+	fmt.Fprintf(d.output, "Discard tray: %.1f decks of %d\n", d.DiscardDecks(), g.DeckCount)
+	trueCount, _ := d.askFloat("True count? ")
+	trueRight := math.Abs(trueCount-g.CardCounter.TrueCount) <= 1
+	d.score(trueRight)
+
+	fmt.Fprintf(d.output, "%s running count %d, true count %.1f\n", countMark(right && trueRight), g.CardCounter.Count, g.CardCounter.TrueCount)
+
+	if d.Progressive {
+		if right && trueRight {
+			d.Speed = d.Speed * 4 / 5
+			if d.Speed < d.MinSpeed {
+				d.Speed = d.MinSpeed
+			}
+		} else {
+			d.Speed = d.Speed * 5 / 4
+		}
+		fmt.Fprintf(d.output, "Speed: %s per flash\n", d.Speed)
+	}
+	fmt.Fprintln(d.output)
+
+	return right && trueRight
+}
+
+func countMark(right bool) string {
+	if right {
+		return "Correct!"
+	}
+	return "Wrong,"
+}
+
+// Countdown runs through a single deck one card per enter, all but the
+// last card.  The count left over tells the last card, so it is timed
+// and checked with a balanced count
+func (d *CountDrill) Countdown() (time.Duration, bool) {
+
+	deck := cards.NewDeck(cards.WithNumberOfDecks(1), cards.WithRandom(d.random))
+	count := 0
+	start := time.Now()
+
+	fmt.Fprintln(d.output, "Press enter for each card.  Count down the deck as fast as you can")
+	for i, card := range deck.Cards[:len(deck.Cards)-1] {
+		count, _ = d.Game.CountCards(card, count, i+1, 1)
+		fmt.Fprint(d.output, card.Render())
+		BufferedReader(d.input).ReadString('\n')
+	}
+	elapsed := time.Since(start)
+
+	running, _ := d.askInt("Running count? ")
+	right := running == count
+	d.score(right)
+
+	last := deck.Cards[len(deck.Cards)-1]
+	fmt.Fprintf(d.output, "%s the count is %d and the last card is %s.  Time: %.1fs\n\n", countMark(right), count, last.Render(), elapsed.Seconds())
+
+	return elapsed, right
+}
+
+// Conversion quizzes turning a running count into a true count using
+// the number of decks in the discard tray
+func (d *CountDrill) Conversion() bool {
+
+	decks := d.Game.DeckCount
+	discard := float64(d.random.Intn(decks*2-1)+1) / 2
+	running := d.random.Intn(25) - 12
+	want := float64(running) / (float64(decks) - discard)
+
+	fmt.Fprintf(d.output, "Running count %+d, discard tray %.1f decks of %d\n", running, discard, decks)
+	trueCount, _ := d.askFloat("True count? ")
+	right := math.Abs(trueCount-want) <= 0.5
+	d.score(right)
+
+	fmt.Fprintf(d.output, "%s true count is %.1f\n\n", countMark(right), want)
+
+	return right
+}
+
+// Report is the drill score
+func (d *CountDrill) Report(output io.Writer) {
+
+	percent := 0.0
+	if d.Asked > 0 {
+		percent = float64(d.Correct) / float64(d.Asked) * 100
+	}
+
+	fmt.Fprintf(output, "************** %s Count Drill Report **************\n", d.Game.CountSystem)
+	fmt.Fprintf(output, "%d of %d correct (%.1f%%), final speed %s per flash\n", d.Correct, d.Asked, percent, d.Speed)
+}
@@ -0,0 +1,76 @@
+package blackjack_test
+
+import (
+	"blackjack"
+	"io"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mbarley333/cards"
+)
+
+func TestCountDrillRound(t *testing.T) {
+	t.Parallel()
+
+	stack := []cards.Card{
+		{Rank: cards.Five, Suit: cards.Club},
+		{Rank: cards.King, Suit: cards.Club},
+		{Rank: cards.Two, Suit: cards.Heart},
+		{Rank: cards.Three, Suit: cards.Spade},
+	}
+
+	g, err := blackjack.NewBlackjackGame(
+		blackjack.WithCustomDeck(cards.Deck{Cards: stack}),
+		blackjack.WithIncomingDeck(false),
+		blackjack.WithOutput(io.Discard),
+		blackjack.WithRendering(false),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	input := strings.NewReader("2\n0\n")
+	d := blackjack.NewCountDrill(g, io.Discard, input, rand.New(rand.NewSource(1)))
+	d.Pause = func(time.Duration) {}
+	d.Group = 2
+	d.Progressive = true
+
+	if !d.Round(4) {
+		t.Fatal("want round counted right")
+	}
+
+	want := 800 * time.Millisecond
+	got := d.Speed
+
+	if want != got {
+		t.Fatalf("want: %s, got: %s", want, got)
+	}
+
+	if d.Asked != 2 || d.Correct != 2 {
+		t.Fatalf("want 2 of 2 correct, got: %d of %d", d.Correct, d.Asked)
+	}
+}
+
+func TestCountDrillCountdown(t *testing.T) {
+	t.Parallel()
+
+	g, err := blackjack.NewBlackjackGame(
+		blackjack.WithOutput(io.Discard),
+		blackjack.WithRendering(false),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a balanced count leaves minus the value of the last card, which
+	// is not known here, so only a wrong answer can be checked
+	input := strings.NewReader(strings.Repeat("\n", 51) + "99\n")
+	d := blackjack.NewCountDrill(g, io.Discard, input, rand.New(rand.NewSource(1)))
+
+	_, right := d.Countdown()
+	if right {
+		t.Fatal("want a running count of 99 to be wrong")
+	}
+}
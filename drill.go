@@ -0,0 +1,176 @@
+package blackjack
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"sort"
+
+	"github.com/mbarley333/cards"
+)
+
+var drillSuits = []cards.Suit{cards.Club, cards.Diamond, cards.Heart, cards.Spade}
+
+// Drill quizzes the player on starting hands against dealer upcards
+// without playing the rounds out.  Answers are scored against the
+// strategy chart as they are given
+type Drill struct {
+	Strategy   Strategy
+	Weighted   bool
+	Asked      int
+	Correct    int
+	Streak     int
+	BestStreak int
+	Cells      map[string]*DrillCell
+	output     io.Writer
+	input      io.Reader
+	random     *rand.Rand
+}
+
+// DrillCell is the record of answers for one chart cell
+type DrillCell struct {
+	Asked   int
+	Correct int
+}
+
+func NewDrill(output io.Writer, input io.Reader, random *rand.Rand) *Drill {
+	return &Drill{
+		Cells:  map[string]*DrillCell{},
+		output: output,
+		input:  BufferedReader(input),
+		random: random,
+	}
+}
+
+func (d *Drill) card() cards.Card {
+	return cards.Card{
+		Rank: cards.Rank(d.random.Intn(13) + 1),
+		Suit: drillSuits[d.random.Intn(len(drillSuits))],
+	}
+}
+
+// Deal is a two card hand, never a blackjack, and a dealer upcard.  A
+// weighted drill favours the hard decisions and the cells the player
+// has been getting wrong
+func (d *Drill) Deal() (*Hand, cards.Card) {
+
+	for {
+		hand := &Hand{Id: 1, Bet: 1, Cards: []cards.Card{d.card(), d.card()}}
+		upcard := d.card()
+
+		if hand.IsBlackjack() {
+			continue
+		}
+		if !d.Weighted || d.random.Intn(12) < d.weight(hand, upcard) {
+			return hand, upcard
+		}
+	}
+}
+
+// weight is how keen a weighted drill is to ask about the hand, out of 12
+func (d *Drill) weight(hand *Hand, upcard cards.Card) int {
+
+	weight := 1
+	score := hand.Score()
+	switch hand.Category() {
+	case CategoryPair, CategorySoft:
+		weight = 4
+	case CategoryHard:
+		if score >= 9 && score <= 16 {
+			weight = 4
+		}
+	}
+
+	if cell, ok := d.Cells[hand.Cell(upcard)]; ok {
+		weight += 4 * (cell.Asked - cell.Correct)
+	}
+
+	return weight
+}
+
+// Ask shows the hand and upcard, takes the player's action through the
+// same dialog as the game and scores it
+func (d *Drill) Ask(hand *Hand, upcard cards.Card) (Action, Action) {
+
+	player := &Player{
+		Name:  "Player",
+		Cash:  1000,
+		Hands: []*Hand{hand},
+	}
+
+	c := CardCounter{}
+	if d.Strategy == StrategyCount {
+		c.TrueCount = float64(d.random.Intn(11) - 4)
+		fmt.Fprintf(d.output, "True count: %+.0f\n", c.TrueCount)
+	}
+
+	fmt.Fprint(d.output, "Dealer shows "+upcard.Render()+"\n")
+	fmt.Fprint(d.output, hand.HandString(player.Name, ThemeDefault))
+
+	answer := HumanAction(d.output, d.input, player, upcard, 0, c, StageDeciding)
+	correct := StrategyActionMap[d.Strategy](io.Discard, nil, player, upcard, 0, c, StageDeciding)
+
+	cellName := hand.Cell(upcard)
+	cell, ok := d.Cells[cellName]
+	if !ok {
+		cell = &DrillCell{}
+		d.Cells[cellName] = cell
+	}
+
+	d.Asked++
+	cell.Asked++
+	if answer == correct {
+		d.Correct++
+		cell.Correct++
+		d.Streak++
+		if d.Streak > d.BestStreak {
+			d.BestStreak = d.Streak
+		}
+		fmt.Fprintf(d.output, "Correct!  Streak: %d\n\n", d.Streak)
+	} else {
+		d.Streak = 0
+		cost := ActionEv(hand, upcard, correct) - ActionEv(hand, upcard, answer)
+		fmt.Fprintf(d.output, "Wrong, %s is %s (costs %.3f bets)\n\n", cellName, correct, cost)
+	}
+
+	return answer, correct
+}
+
+// Run asks about the given number of hands and reports the results
+func (d *Drill) Run(hands int) {
+
+	for i := 0; i < hands; i++ {
+		hand, upcard := d.Deal()
+		d.Ask(hand, upcard)
+	}
+
+	d.Report(d.output)
+}
+
+// Report is the drill score with the weakest cells first
+func (d *Drill) Report(output io.Writer) {
+
+	fmt.Fprintln(output, "************** Drill Report **************")
+	percent := 0.0
+	if d.Asked > 0 {
+		percent = float64(d.Correct) / float64(d.Asked) * 100
+	}
+	fmt.Fprintf(output, "%d of %d correct (%.1f%%), best streak %d\n", d.Correct, d.Asked, percent, d.BestStreak)
+
+	names := []string{}
+	for name := range d.Cells {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		a, b := d.Cells[names[i]], d.Cells[names[j]]
+		if a.Asked-a.Correct != b.Asked-b.Correct {
+			return a.Asked-a.Correct > b.Asked-b.Correct
+		}
+		return names[i] < names[j]
+	})
+
+	for _, name := range names {
+		cell := d.Cells[name]
+		fmt.Fprintf(output, "  %s: %d of %d\n", name, cell.Correct, cell.Asked)
+	}
+}
@@ -0,0 +1,55 @@
+package blackjack_test
+
+import (
+	"blackjack"
+	"io"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/mbarley333/cards"
+)
+
+func TestDrillScoresAnswers(t *testing.T) {
+	t.Parallel()
+
+	input := strings.NewReader("s\nh\nh\n")
+	d := blackjack.NewDrill(io.Discard, input, rand.New(rand.NewSource(1)))
+
+	upcard := cards.Card{Rank: cards.Ten, Suit: cards.Spade}
+	for i := 0; i < 3; i++ {
+		hand := &blackjack.Hand{Id: 1, Bet: 1, Cards: []cards.Card{{Rank: cards.Ten, Suit: cards.Club}, {Rank: cards.Six, Suit: cards.Heart}}}
+		d.Ask(hand, upcard)
+	}
+
+	want := map[string]*blackjack.DrillCell{
+		"hard 16 v 10": {Asked: 3, Correct: 2},
+	}
+	got := d.Cells
+
+	if !cmp.Equal(want, got) {
+		t.Fatal(cmp.Diff(want, got))
+	}
+
+	if d.Streak != 2 || d.BestStreak != 2 {
+		t.Fatalf("want streak 2 and best streak 2, got: %d and %d", d.Streak, d.BestStreak)
+	}
+}
+
+func TestDrillDeal(t *testing.T) {
+	t.Parallel()
+
+	d := blackjack.NewDrill(io.Discard, strings.NewReader(""), rand.New(rand.NewSource(1)))
+	d.Weighted = true
+
+	for i := 0; i < 500; i++ {
+		hand, _ := d.Deal()
+		if len(hand.Cards) != 2 {
+			t.Fatalf("want 2 cards, got: %d", len(hand.Cards))
+		}
+		if hand.IsBlackjack() {
+			t.Fatal("want no blackjack dealt in a drill")
+		}
+	}
+}
@@ -0,0 +1,491 @@
+package blackjack
+
+import (
+	"fmt"
+
+	"github.com/mbarley333/cards"
+)
+
+type DecisionType int
+
+const (
+	DecisionNone DecisionType = iota
+	DecisionBet
+	DecisionSwitch
+	DecisionAction
+	DecisionRoundOver
+)
+
+var DecisionTypeMap = map[DecisionType]string{
+	DecisionNone:      "Invalid Decision",
+	DecisionBet:       "Bet",
+	DecisionSwitch:    "Switch",
+	DecisionAction:    "Action",
+	DecisionRoundOver: "Round Over",
+}
+
+func (d DecisionType) String() string {
+	return DecisionTypeMap[d]
+}
+
+// Decision is the next input the engine needs before the round can
+// move on.  HandIndex points at the hand being bet on or played
+type Decision struct {
+	Type       DecisionType
+	Player     *Player
+	HandIndex  int
+	DealerCard cards.Card
+	Allowed    []Action
+}
+
+type cursor struct {
+	player int
+	hand   int
+}
+
+// step holds the engine to one stage, and to one player's hands when
+// player is set, for the deprecated stage by stage round
+type step struct {
+	stage  Stage
+	player *Player
+}
+
+// StartRound clears the table and asks for the first bet.  The round
+// then advances only through PlaceBet and ApplyAction
+func (g *Game) StartRound() Decision {
+
+	g.Players = g.RemoveQuitPlayers()
+	g.ResetPlayers()
+	g.SetStage(StageBetting)
+	g.cursor = cursor{}
+
+	return g.advance()
+}
+
+// Pending returns the decision the engine is waiting on
+func (g *Game) Pending() Decision {
+	return g.pending
+}
+
+// PlaceBet bets on the spot of the pending bet decision.  A bet of 0
+// leaves the spot empty for the round
+func (g *Game) PlaceBet(player *Player, bet int) (Decision, error) {
+
+	err := g.checkPending(DecisionBet, player)
+	if err != nil {
+		return g.pending, err
+	}
+
+	if bet < 0 || bet > player.MaxBet() || (bet > 0 && bet < player.MinBet()) {
+		return g.pending, fmt.Errorf("bet must be between $%d and $%d, or $0 to sit the spot out", player.MinBet(), player.MaxBet())
+	}
+
+	if bet > 0 {
+		player.CurrentBet = bet
+		player.PlaceBet(g.pending.HandIndex, bet)
+	}
+	g.cursor.hand++
+
+	return g.advance(), nil
+}
+
+// ConfirmBets accepts whatever bets the player's own betting routine
+// has already placed on their hands and moves on to the next player
+func (g *Game) ConfirmBets(player *Player) (Decision, error) {
+
+	err := g.checkPending(DecisionBet, player)
+	if err != nil {
+		return g.pending, err
+	}
+
+	g.cursor.hand = len(player.Hands)
+
+	return g.advance(), nil
+}
+
+// ApplyAction plays the action for the pending decision.  Quit is
+// accepted while betting, switch or keep while switching and any of
+// the allowed actions while deciding
+func (g *Game) ApplyAction(player *Player, action Action) (Decision, error) {
+
+	switch g.pending.Type {
+	case DecisionBet:
+		err := g.checkPending(DecisionBet, player)
+		if err != nil {
+			return g.pending, err
+		}
+		if action != ActionQuit {
+			return g.pending, fmt.Errorf("%s is not allowed while betting, place a bet or quit", action)
+		}
+		player.Action = ActionQuit
+		g.Emit(Event{Type: EventActionTaken, Player: player.Name, Seat: player.Seat, Action: ActionQuit})
+		g.cursor.hand = len(player.Hands)
+
+	case DecisionSwitch:
+		err := g.checkPending(DecisionSwitch, player)
+		if err != nil {
+			return g.pending, err
+		}
+		if action != ActionSwitch && action != ActionKeep {
+			return g.pending, fmt.Errorf("%s is not allowed, choose switch or keep", action)
+		}
+		g.switchCards(player, g.pending.HandIndex, action)
+		g.cursor.hand++
+
+	case DecisionAction:
+		err := g.checkPending(DecisionAction, player)
+		if err != nil {
+			return g.pending, err
+		}
+		if !isAllowed(action, g.pending.Allowed) {
+			return g.pending, fmt.Errorf("%s is not allowed, choose from %v", action, g.pending.Allowed)
+		}
+		g.playAction(player, g.pending.HandIndex, action)
+
+	default:
+		return g.pending, fmt.Errorf("no decision is pending, start a new round")
+	}
+
+	return g.advance(), nil
+}
+
+func (g *Game) checkPending(decisionType DecisionType, player *Player) error {
+
+	if g.pending.Type != decisionType {
+		return fmt.Errorf("engine is waiting for %s, not %s", g.pending.Type, decisionType)
+	}
+
+	if g.pending.Player != player {
+		return fmt.Errorf("it is %s's turn", g.pending.Player.Name)
+	}
+
+	return nil
+}
+
+// advance runs the round forward until an input is needed
+func (g *Game) advance() Decision {
+
+	for {
+		switch g.Stage {
+		case StageBetting:
+			for g.cursor.player < len(g.Players) {
+				player := g.Players[g.cursor.player]
+				indexes := player.SpotIndexes()
+				if player.Action != ActionQuit && !player.SittingOut && g.cursor.hand < len(indexes) {
+					player.HandIndex = indexes[g.cursor.hand]
+					return g.decide(DecisionBet, player, indexes[g.cursor.hand])
+				}
+				player.HandIndex = 0
+				g.betsPlaced(player)
+				g.cursor = cursor{player: g.cursor.player + 1}
+			}
+
+			if g.step.stage == StageBetting {
+				return g.stepped()
+			}
+
+			// nothing is dealt when everyone left or is sitting out
+			g.Players = g.RemoveQuitPlayers()
+			if !g.handsBet() {
+				return g.endRound()
+			}
+
+			g.SetStage(StageOpeningDeal)
+			g.dealOpeningCards()
+
+			if g.Variant == VariantSwitch {
+				g.SetStage(StageSwitching)
+			} else {
+				g.SetStage(StageDeciding)
+			}
+			g.cursor = cursor{}
+
+		case StageSwitching:
+			for g.cursor.player < len(g.Players) {
+				player := g.Players[g.cursor.player]
+				indexes := player.SwitchIndexes()
+				if g.cursor.hand < len(indexes) {
+					player.HandIndex = indexes[g.cursor.hand]
+					return g.decide(DecisionSwitch, player, indexes[g.cursor.hand])
+				}
+				player.HandIndex = 0
+				g.cursor = cursor{player: g.cursor.player + 1}
+			}
+
+			if g.step.stage == StageSwitching {
+				return g.stepped()
+			}
+
+			g.SetStage(StageDeciding)
+			g.cursor = cursor{}
+
+		case StageDeciding:
+			for g.cursor.player < len(g.Players) {
+				player := g.Players[g.cursor.player]
+				for g.cursor.hand < len(player.Hands) {
+					hand := player.Hands[g.cursor.hand]
+					g.checkBlackjack(hand)
+					if hand.ChooseAction() {
+						player.HandIndex = g.cursor.hand
+						return g.decide(DecisionAction, player, g.cursor.hand)
+					}
+					g.cursor.hand++
+				}
+				player.HandIndex = 0
+				if g.step.player == player {
+					return g.stepped()
+				}
+				g.cursor = cursor{player: g.cursor.player + 1}
+			}
+
+			if g.step.stage == StageDeciding {
+				return g.stepped()
+			}
+
+			g.SetStage(StageDealerPlay)
+			g.dealerDraw()
+			g.SetStage(StageOutcome)
+			g.settle()
+
+			return g.endRound()
+
+		default:
+			return g.endRound()
+		}
+	}
+}
+
+// handsBet is true when any player has a hand bet on
+func (g *Game) handsBet() bool {
+	for _, player := range g.Players {
+		if len(player.Hands) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *Game) decide(decisionType DecisionType, player *Player, index int) Decision {
+
+	g.SetActivePlayer(player)
+
+	decision := Decision{
+		Type:      decisionType,
+		Player:    player,
+		HandIndex: index,
+	}
+
+	if len(g.Dealer.Hands[0].Cards) > 1 {
+		decision.DealerCard = g.Dealer.Hands[0].Cards[1]
+	}
+
+	switch decisionType {
+	case DecisionBet:
+		decision.Allowed = []Action{ActionBet, ActionQuit}
+	case DecisionSwitch:
+		decision.Allowed = []Action{ActionSwitch, ActionKeep}
+	case DecisionAction:
+		decision.Allowed = player.AllowedActions(index)
+		// both dealer cards are visible to the player in Double Exposure
+		if g.Variant == VariantDoubleExposure {
+			player.DealerHand = g.Dealer.Hands[0]
+		}
+	}
+
+	g.pending = decision
+	return decision
+}
+
+// stepped ends the stage played by the deprecated stage by stage round
+// with no decision pending
+func (g *Game) stepped() Decision {
+	g.pending = Decision{}
+	return g.pending
+}
+
+func (g *Game) endRound() Decision {
+
+	g.Emit(Event{Type: EventRoundEnded})
+	g.pending = Decision{Type: DecisionRoundOver}
+
+	return g.pending
+}
+
+func isAllowed(action Action, allowed []Action) bool {
+	for _, a := range allowed {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
+
+// betsPlaced drops the spots the player did not bet on and reports the bets
+func (g *Game) betsPlaced(player *Player) {
+
+	if player.Action != ActionQuit {
+		player.RemoveEmptySpots()
+	}
+
+	for _, hand := range player.Hands {
+		if hand.Bet > 0 {
+			g.Emit(Event{Type: EventBetPlaced, Player: player.Name, Seat: player.Seat, HandId: hand.Id, Bet: hand.Bet, Cash: player.Cash})
+		}
+	}
+}
+
+// dealOpeningCards deals two rounds of cards to every hand and the
+// dealer.  The dealer's first card is the hole card
+func (g *Game) dealOpeningCards() {
+
+	for i := 0; i < 2; i++ {
+		for _, player := range g.Players {
+			g.SetActivePlayer(player)
+			for _, hand := range player.Hands {
+				card := g.Deal(g.output)
+				hand.Cards = append(hand.Cards, card)
+				g.EmitCardDealt(player, hand, card, false)
+			}
+		}
+		card := g.Deal(g.output)
+		g.Dealer.Hands[0].Cards = append(g.Dealer.Hands[0].Cards, card)
+		g.EmitCardDealt(g.Dealer, g.Dealer.Hands[0], card, i == 0 && g.Variant != VariantDoubleExposure)
+	}
+}
+
+// checkBlackjack marks a two card 21 as a blackjack, unless switching
+// made it
+func (g *Game) checkBlackjack(hand *Hand) {
+	if len(hand.Cards) == 2 && hand.Outcome == OutcomeNone && !hand.Switched && hand.Score() == 21 {
+		hand.Outcome = OutcomeBlackjack
+	}
+}
+
+// playAction hits, doubles, splits or stands the hand at index
+func (g *Game) playAction(player *Player, index int, action Action) {
+
+	hand := player.Hands[index]
+	hand.Action = action
+	g.Emit(Event{Type: EventActionTaken, Player: player.Name, Seat: player.Seat, HandId: hand.Id, Action: action})
+
+	switch action {
+	case ActionHit:
+		card := g.Deal(g.output)
+		hand.Hit(g.output, card, player.Name)
+		g.EmitCardDealt(player, hand, card, false)
+	case ActionDoubleDown:
+		free := player.IsFreeDouble(index)
+		if !free {
+			player.Cash -= hand.Bet + hand.FreeBet
+		}
+		card := g.Deal(g.output)
+		hand.DoubleDown(g.output, card, player.Name, free)
+		g.EmitCardDealt(player, hand, card, true)
+	case ActionSplit:
+		free := player.IsFreeSplit(index)
+		card1 := g.Deal(g.output)
+		card2 := g.Deal(g.output)
+		player.Split(g.output, card1, card2, index, free)
+		g.EmitCardDealt(player, player.Hands[index], card1, false)
+		g.EmitCardDealt(player, player.Hands[index+1], card2, false)
+	}
+}
+
+// switchCards plays the switch or keep for the spot starting at index
+func (g *Game) switchCards(player *Player, index int, action Action) {
+
+	g.Emit(Event{Type: EventActionTaken, Player: player.Name, Seat: player.Seat, HandId: player.Hands[index].Id, Action: action})
+
+	if action == ActionSwitch {
+		player.SwitchCards(index)
+	}
+	player.Action = None
+}
+
+// dealerDraw turns over the hole card and draws to 17, hitting soft 17
+func (g *Game) dealerDraw() {
+
+	hand := g.Dealer.Hands[0]
+	g.Emit(Event{Type: EventHoleCardRevealed, Player: g.Dealer.Name, HandId: hand.Id, Card: hand.Cards[0]})
+
+	if !g.IsDealerDraw() {
+		return
+	}
+
+	for hand.Score() <= 16 || (hand.Score() == 17 && hand.MinScore() != 17) {
+		card := g.Deal(g.output)
+		hand.Cards = append(hand.Cards, card)
+		g.EmitCardDealt(g.Dealer, hand, card, false)
+	}
+	hand.Action = ActionStand
+}
+
+// HandOutcome scores a finished hand against the dealer
+func (g *Game) HandOutcome(hand *Hand) Outcome {
+
+	var outcome Outcome
+	dealerHand := g.Dealer.Hands[0]
+
+	if g.Variant == VariantDoubleExposure && hand.Outcome == OutcomeBlackjack && dealerHand.IsBlackjack() {
+		if g.BlackjackTiePush {
+			outcome = OutcomeTie
+		} else {
+			outcome = OutcomeLose
+		}
+	} else if hand.Outcome == OutcomeBlackjack || hand.Outcome == OutcomeBust {
+		outcome = hand.Outcome
+	} else if VariantDealerPush22Map[g.Variant] && dealerHand.Score() == 22 {
+		// dealer 22 pushes all hands still in play
+		outcome = OutcomeTie
+	} else if dealerHand.Score() > 21 {
+		outcome = OutcomeWin
+	} else if hand.Score() > dealerHand.Score() {
+		outcome = OutcomeWin
+	} else if hand.Score() < dealerHand.Score() {
+		outcome = OutcomeLose
+	} else if g.Variant == VariantDoubleExposure {
+		// dealer wins all ties in Double Exposure
+		outcome = OutcomeLose
+	} else {
+		outcome = OutcomeTie
+	}
+
+	return outcome
+}
+
+// settle decides every hand, pays it out and updates records
+func (g *Game) settle() {
+
+	for _, player := range g.Players {
+		for _, hand := range player.Hands {
+			hand.Outcome = g.HandOutcome(hand)
+		}
+
+		player.SetWinLoseTie()
+
+		bets := []int{}
+		for _, hand := range player.Hands {
+			bets = append(bets, hand.Bet)
+		}
+
+		player.Payout()
+		if len(player.Hands) > 0 {
+			player.RoundsPlayed++
+		}
+
+		for index, hand := range player.Hands {
+			g.Emit(Event{
+				Type:    EventHandSettled,
+				Player:  player.Name,
+				Seat:    player.Seat,
+				HandId:  hand.Id,
+				Bet:     bets[index],
+				Outcome: hand.Outcome,
+				Payout:  hand.Payout,
+				Cash:    player.Cash,
+			})
+		}
+
+		player.Broke()
+	}
+}
@@ -0,0 +1,261 @@
+package blackjack_test
+
+import (
+	"blackjack"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/mbarley333/cards"
+)
+
+func TestEngineRound(t *testing.T) {
+	t.Parallel()
+
+	stack := []cards.Card{
+		{Rank: cards.Ten, Suit: cards.Club},
+		{Rank: cards.Seven, Suit: cards.Club},
+		{Rank: cards.Six, Suit: cards.Club},
+		{Rank: cards.King, Suit: cards.Spade},
+		{Rank: cards.Five, Suit: cards.Heart},
+	}
+
+	deck := cards.Deck{
+		Cards: stack,
+	}
+
+	g, err := blackjack.NewBlackjackGame(
+		blackjack.WithCustomDeck(deck),
+		blackjack.WithIncomingDeck(false),
+		blackjack.WithRendering(false),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := &blackjack.Player{
+		Name: "Ironhide",
+		Cash: 100,
+	}
+	g.AddPlayer(p)
+
+	d := g.StartRound()
+	if d.Type != blackjack.DecisionBet || d.Player != p {
+		t.Fatalf("want: bet decision for %s, got: %s", p.Name, d.Type)
+	}
+
+	d, err = g.PlaceBet(p, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []blackjack.Action{blackjack.ActionHit, blackjack.ActionStand, blackjack.ActionDoubleDown}
+	got := d.Allowed
+
+	if !cmp.Equal(want, got) {
+		t.Fatal(cmp.Diff(want, got))
+	}
+
+	wantCard := cards.Card{Rank: cards.King, Suit: cards.Spade}
+	gotCard := d.DealerCard
+
+	if !cmp.Equal(wantCard, gotCard) {
+		t.Fatal(cmp.Diff(wantCard, gotCard))
+	}
+
+	_, err = g.ApplyAction(p, blackjack.ActionSplit)
+	if err == nil {
+		t.Fatal("want error splitting a hand that is not a pair")
+	}
+
+	d, err = g.ApplyAction(p, blackjack.ActionHit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d, err = g.ApplyAction(p, blackjack.ActionStand)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if d.Type != blackjack.DecisionRoundOver {
+		t.Fatalf("want: %s, got: %s", blackjack.DecisionRoundOver, d.Type)
+	}
+
+	wantOutcome := blackjack.OutcomeWin
+	gotOutcome := p.Hands[0].Outcome
+
+	if wantOutcome != gotOutcome {
+		t.Fatalf("want: %q, got: %q", wantOutcome.String(), gotOutcome.String())
+	}
+
+	wantCash := 110
+	gotCash := p.Cash
+
+	if wantCash != gotCash {
+		t.Fatalf("want: %d, got: %d", wantCash, gotCash)
+	}
+}
+
+func TestEngineTurnOrder(t *testing.T) {
+	t.Parallel()
+
+	g, err := blackjack.NewBlackjackGame(
+		blackjack.WithRendering(false),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p1 := &blackjack.Player{Name: "Wheeljack", Cash: 100}
+	p2 := &blackjack.Player{Name: "Ratchet", Cash: 100}
+	g.AddPlayer(p1)
+	g.AddPlayer(p2)
+
+	g.StartRound()
+
+	_, err = g.PlaceBet(p2, 5)
+	if err == nil {
+		t.Fatal("want error betting out of turn")
+	}
+
+	d, err := g.ApplyAction(p1, blackjack.ActionQuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if d.Player != p2 {
+		t.Fatalf("want: %s to bet, got: %s", p2.Name, d.Player.Name)
+	}
+
+	_, err = g.PlaceBet(p2, 101)
+	if err == nil {
+		t.Fatal("want error betting more than the player has")
+	}
+}
+
+func TestEngineSwitchEachSpot(t *testing.T) {
+	t.Parallel()
+
+	stack := []cards.Card{
+		{Rank: cards.Ten, Suit: cards.Club},
+		{Rank: cards.Five, Suit: cards.Club},
+		{Rank: cards.Nine, Suit: cards.Club},
+		{Rank: cards.Eight, Suit: cards.Club},
+		{Rank: cards.Seven, Suit: cards.Spade},
+		{Rank: cards.Five, Suit: cards.Heart},
+		{Rank: cards.Ten, Suit: cards.Heart},
+		{Rank: cards.Seven, Suit: cards.Heart},
+		{Rank: cards.Two, Suit: cards.Heart},
+		{Rank: cards.Ten, Suit: cards.Spade},
+	}
+
+	g, err := blackjack.NewBlackjackGame(
+		blackjack.WithCustomDeck(cards.Deck{Cards: stack}),
+		blackjack.WithIncomingDeck(false),
+		blackjack.WithRendering(false),
+		blackjack.WithVariant(blackjack.VariantSwitch),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := &blackjack.Player{
+		Name:  "Bumblebee",
+		Cash:  100,
+		Spots: 2,
+	}
+	g.AddPlayer(p)
+
+	d := g.StartRound()
+	for d.Type == blackjack.DecisionBet {
+		d, err = g.PlaceBet(p, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if d.Type != blackjack.DecisionSwitch || d.HandIndex != 0 {
+		t.Fatalf("want: switch decision for the first spot, got: %s on hand %d", d.Type, d.HandIndex)
+	}
+
+	d, err = g.ApplyAction(p, blackjack.ActionKeep)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if d.Type != blackjack.DecisionSwitch || d.Player != p || d.HandIndex != 2 {
+		t.Fatalf("want: switch decision for the second spot, got: %s on hand %d", d.Type, d.HandIndex)
+	}
+
+	d, err = g.ApplyAction(p, blackjack.ActionSwitch)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if d.Type != blackjack.DecisionAction {
+		t.Fatalf("want: %s, got: %s", blackjack.DecisionAction, d.Type)
+	}
+
+	want := [][]cards.Card{
+		{{Rank: cards.Ten, Suit: cards.Club}, {Rank: cards.Five, Suit: cards.Heart}},
+		{{Rank: cards.Five, Suit: cards.Club}, {Rank: cards.Ten, Suit: cards.Heart}},
+		{{Rank: cards.Nine, Suit: cards.Club}, {Rank: cards.Two, Suit: cards.Heart}},
+		{{Rank: cards.Eight, Suit: cards.Club}, {Rank: cards.Seven, Suit: cards.Heart}},
+	}
+	got := [][]cards.Card{}
+	for _, hand := range p.Hands {
+		got = append(got, hand.Cards)
+	}
+
+	if !cmp.Equal(want, got) {
+		t.Fatal(cmp.Diff(want, got))
+	}
+}
+
+func TestEngineSwitchedTwentyOneIsNotBlackjack(t *testing.T) {
+	t.Parallel()
+
+	stack := []cards.Card{
+		{Rank: cards.Ace, Suit: cards.Club},
+		{Rank: cards.Nine, Suit: cards.Club},
+		{Rank: cards.Seven, Suit: cards.Spade},
+		{Rank: cards.Five, Suit: cards.Heart},
+		{Rank: cards.King, Suit: cards.Heart},
+		{Rank: cards.Ten, Suit: cards.Spade},
+	}
+
+	g, err := blackjack.NewBlackjackGame(
+		blackjack.WithCustomDeck(cards.Deck{Cards: stack}),
+		blackjack.WithIncomingDeck(false),
+		blackjack.WithRendering(false),
+		blackjack.WithVariant(blackjack.VariantSwitch),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := &blackjack.Player{
+		Name: "Optimus",
+		Cash: 100,
+	}
+	g.AddPlayer(p)
+
+	g.StartRound()
+	_, err = g.PlaceBet(p, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := g.ApplyAction(p, blackjack.ActionSwitch)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if d.Type != blackjack.DecisionAction || d.HandIndex != 0 {
+		t.Fatalf("want: the switched 21 played, got: %s on hand %d", d.Type, d.HandIndex)
+	}
+
+	if p.Hands[0].Score() != 21 || p.Hands[0].Outcome == blackjack.OutcomeBlackjack {
+		t.Fatalf("want: a switched 21 that is not a blackjack, got: %d %s", p.Hands[0].Score(), p.Hands[0].Outcome)
+	}
+}
@@ -0,0 +1,178 @@
+package blackjack
+
+import (
+	"github.com/mbarley333/cards"
+)
+
+// the chance of drawing each card value from an infinite deck.  index
+// 1 is the ace and index 10 covers tens and faces
+var cardValueChance = [11]float64{0, 1.0 / 13, 1.0 / 13, 1.0 / 13, 1.0 / 13, 1.0 / 13, 1.0 / 13, 1.0 / 13, 1.0 / 13, 1.0 / 13, 4.0 / 13}
+
+// dealerTotals is the chance of the dealer finishing on 17 to 21, with
+// busting in the last place
+type dealerTotals [6]float64
+
+// evTable works out expected values for one dealer upcard
+type evTable struct {
+	dealer dealerTotals
+	hits   map[int]float64
+}
+
+func newEvTable(upcard cards.Card) *evTable {
+	value := min(int(upcard.Rank), 10)
+	return &evTable{
+		dealer: dealerFinish(value, value == 1),
+		hits:   map[int]float64{},
+	}
+}
+
+// bestTotal counts one ace as 11 when that does not bust the hand
+func bestTotal(total int, ace bool) int {
+	if ace && total+10 <= 21 {
+		return total + 10
+	}
+	return total
+}
+
+// dealerFinish draws for the dealer from total, hitting soft 17
+func dealerFinish(total int, ace bool) dealerTotals {
+
+	result := dealerTotals{}
+	best := bestTotal(total, ace)
+	soft := best != total
+
+	if best > 21 {
+		result[5] = 1
+		return result
+	}
+	if best > 17 || best == 17 && !soft {
+		result[best-17] = 1
+		return result
+	}
+
+	for value := 1; value <= 10; value++ {
+		next := dealerFinish(total+value, ace || value == 1)
+		for i := range result {
+			result[i] += cardValueChance[value] * next[i]
+		}
+	}
+
+	return result
+}
+
+func (t *evTable) stand(total int) float64 {
+
+	if total > 21 {
+		return -1
+	}
+
+	ev := t.dealer[5]
+	for i := 0; i < 5; i++ {
+		dealerTotal := 17 + i
+		if total > dealerTotal {
+			ev += t.dealer[i]
+		} else if total < dealerTotal {
+			ev -= t.dealer[i]
+		}
+	}
+
+	return ev
+}
+
+// hit takes a card and then plays on as well as possible
+func (t *evTable) hit(total int, ace bool) float64 {
+
+	key := total*2 + boolIndex(ace)
+	if ev, ok := t.hits[key]; ok {
+		return ev
+	}
+
+	ev := 0.0
+	for value := 1; value <= 10; value++ {
+		next := total + value
+		nextAce := ace || value == 1
+		if next > 21 {
+			ev -= cardValueChance[value]
+			continue
+		}
+		stand := t.stand(bestTotal(next, nextAce))
+		hit := t.hit(next, nextAce)
+		if hit > stand {
+			stand = hit
+		}
+		ev += cardValueChance[value] * stand
+	}
+
+	t.hits[key] = ev
+	return ev
+}
+
+// double takes one card for twice the bet
+func (t *evTable) double(total int, ace bool) float64 {
+
+	ev := 0.0
+	for value := 1; value <= 10; value++ {
+		ev += cardValueChance[value] * t.stand(bestTotal(total+value, ace || value == 1))
+	}
+
+	return 2 * ev
+}
+
+// split plays two hands each starting with one card of the pair.  Split
+// aces get one card each and hands are not split again
+func (t *evTable) split(value int) float64 {
+
+	ev := 0.0
+	for next := 1; next <= 10; next++ {
+		total := value + next
+		ace := value == 1 || next == 1
+		best := t.stand(bestTotal(total, ace))
+		if value != 1 {
+			if hit := t.hit(total, ace); hit > best {
+				best = hit
+			}
+			if double := t.double(total, ace); double > best {
+				best = double
+			}
+		}
+		ev += cardValueChance[next] * best
+	}
+
+	return 2 * ev
+}
+
+func boolIndex(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// ActionEv is the expected value, in bets, of taking the action with
+// the hand against the dealer's upcard.  It is worked out for an
+// infinite deck under classic rules with the dealer hitting soft 17
+func ActionEv(hand *Hand, upcard cards.Card, action Action) float64 {
+
+	t := newEvTable(upcard)
+	total := hand.MinScore()
+	ace := false
+	for _, card := range hand.Cards {
+		if card.Rank == cards.Ace {
+			ace = true
+		}
+	}
+
+	var ev float64
+	switch action {
+	case ActionHit:
+		ev = t.hit(total, ace)
+	case ActionDoubleDown:
+		ev = t.double(total, ace)
+	case ActionSplit:
+		ev = t.split(min(int(hand.Cards[0].Rank), 10))
+	default:
+		ev = t.stand(bestTotal(total, ace))
+	}
+
+	return ev
+}
@@ -0,0 +1,97 @@
+package blackjack
+
+import (
+	"github.com/mbarley333/cards"
+)
+
+type EventType int
+
+const (
+	EventNone EventType = iota
+	EventRoundStarted
+	EventBetPlaced
+	EventCardDealt
+	EventHoleCardRevealed
+	EventActionTaken
+	EventHandSettled
+	EventShoeShuffled
+	EventStageChanged
+	EventPlayerSeated
+	EventPlayerLeft
+	EventRoundEnded
+)
+
+var EventTypeMap = map[EventType]string{
+	EventNone:             "Invalid Event",
+	EventRoundStarted:     "RoundStarted",
+	EventBetPlaced:        "BetPlaced",
+	EventCardDealt:        "CardDealt",
+	EventHoleCardRevealed: "HoleCardRevealed",
+	EventActionTaken:      "ActionTaken",
+	EventHandSettled:      "HandSettled",
+	EventShoeShuffled:     "ShoeShuffled",
+	EventStageChanged:     "StageChanged",
+	EventPlayerSeated:     "PlayerSeated",
+	EventPlayerLeft:       "PlayerLeft",
+	EventRoundEnded:       "RoundEnded",
+}
+
+func (e EventType) String() string {
+	return EventTypeMap[e]
+}
+
+// Event is something that happened at the table.  Only the fields
+// that make sense for the event type are set
+type Event struct {
+	Type     EventType
+	Round    int
+	Stage    Stage
+	Player   string
+	Seat     int
+	HandId   int
+	Card     cards.Card
+	FaceDown bool
+	Action   Action
+	Bet      int
+	Outcome  Outcome
+	Payout   int
+	Cash     int
+}
+
+// Subscriber is called for every event the game emits, in order
+type Subscriber func(Event)
+
+func WithSubscriber(subscriber Subscriber) Option {
+	return func(g *Game) error {
+		g.Subscribe(subscriber)
+		return nil
+	}
+}
+
+func (g *Game) Subscribe(subscriber Subscriber) {
+	g.subscribers = append(g.subscribers, subscriber)
+}
+
+// Emit stamps the event with the current round and stage and sends
+// it to every subscriber
+func (g *Game) Emit(event Event) {
+	event.Round = g.Round
+	event.Stage = g.Stage
+
+	for _, subscriber := range g.subscribers {
+		subscriber(event)
+	}
+}
+
+// EmitCardDealt reports a card dealt to a hand.  Face down cards are
+// still sent so consumers such as loggers can record them
+func (g *Game) EmitCardDealt(player *Player, hand *Hand, card cards.Card, faceDown bool) {
+	g.Emit(Event{
+		Type:     EventCardDealt,
+		Player:   player.Name,
+		Seat:     player.Seat,
+		HandId:   hand.Id,
+		Card:     card,
+		FaceDown: faceDown,
+	})
+}
@@ -0,0 +1,105 @@
+package blackjack_test
+
+import (
+	"blackjack"
+	"io"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/mbarley333/cards"
+)
+
+func TestEventStream(t *testing.T) {
+	t.Parallel()
+
+	stack := []cards.Card{
+		{Rank: cards.Ten, Suit: cards.Club},
+		{Rank: cards.Seven, Suit: cards.Club},
+		{Rank: cards.Nine, Suit: cards.Club},
+		{Rank: cards.King, Suit: cards.Spade},
+	}
+
+	deck := cards.Deck{
+		Cards: stack,
+	}
+
+	events := []blackjack.Event{}
+
+	g, err := blackjack.NewBlackjackGame(
+		blackjack.WithCustomDeck(deck),
+		blackjack.WithIncomingDeck(false),
+		blackjack.WithOutput(io.Discard),
+		blackjack.WithSubscriber(func(e blackjack.Event) {
+			events = append(events, e)
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := &blackjack.Player{
+		Name:           "Optimus",
+		Cash:           100,
+		Bet:            blackjack.AiBet,
+		Decide:         blackjack.AiActionStandOnly,
+		AiRoundsToPlay: 1,
+	}
+	g.AddPlayer(p)
+
+	g.ResetPlayers()
+	g.Betting()
+	g.OpeningDeal()
+	g.Deciding()
+	g.DealerPlay()
+	g.Outcome(io.Discard)
+
+	want := []blackjack.EventType{
+		blackjack.EventPlayerSeated,
+		blackjack.EventRoundStarted,
+		blackjack.EventStageChanged,
+		blackjack.EventBetPlaced,
+		blackjack.EventStageChanged,
+		blackjack.EventCardDealt,
+		blackjack.EventCardDealt,
+		blackjack.EventCardDealt,
+		blackjack.EventCardDealt,
+		blackjack.EventStageChanged,
+		blackjack.EventActionTaken,
+		blackjack.EventStageChanged,
+		blackjack.EventHoleCardRevealed,
+		blackjack.EventStageChanged,
+		blackjack.EventHandSettled,
+		blackjack.EventRoundEnded,
+	}
+
+	got := []blackjack.EventType{}
+	for _, e := range events {
+		got = append(got, e.Type)
+	}
+
+	if !cmp.Equal(want, got) {
+		t.Fatal(cmp.Diff(want, got))
+	}
+
+	holeCard := events[6]
+	if !holeCard.FaceDown || holeCard.Player != "Dealer" {
+		t.Fatalf("want dealer hole card dealt face down, got: %+v", holeCard)
+	}
+
+	settled := events[14]
+	wantSettled := blackjack.Event{
+		Type:    blackjack.EventHandSettled,
+		Round:   1,
+		Stage:   blackjack.StageOutcome,
+		Player:  "Optimus",
+		HandId:  1,
+		Bet:     1,
+		Outcome: blackjack.OutcomeWin,
+		Payout:  1,
+		Cash:    101,
+	}
+
+	if !cmp.Equal(wantSettled, settled) {
+		t.Fatal(cmp.Diff(wantSettled, settled))
+	}
+}
@@ -0,0 +1,178 @@
+package blackjack
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/mbarley333/cards"
+)
+
+// HandHistory is one round of play.  A hand history file holds one
+// HandHistory per line as JSON
+type HandHistory struct {
+	ShoeId     int               `json:"shoeId"`
+	Seed       int64             `json:"seed"`
+	Round      int               `json:"round"`
+	Variant    string            `json:"variant"`
+	Decks      int               `json:"decks"`
+	TiePush    bool              `json:"blackjackTiePush"`
+	SeatCount  int               `json:"seatCount"`
+	Seats      []HistorySeat     `json:"seats"`
+	Bets       []HistoryBet      `json:"bets"`
+	Cards      []HistoryCard     `json:"cards"`
+	Decisions  []HistoryDecision `json:"decisions"`
+	DealerHand []cards.Card      `json:"dealerHand"`
+	Outcomes   []HistoryOutcome  `json:"outcomes"`
+}
+
+// HistorySeat is a player at the table when the round starts.  Record
+// is the player's record once the round is over
+type HistorySeat struct {
+	Player string `json:"player"`
+	Seat   int    `json:"seat"`
+	Spots  int    `json:"spots"`
+	Cash   int    `json:"cash"`
+	Record Record `json:"record"`
+}
+
+type HistoryBet struct {
+	Player string `json:"player"`
+	Seat   int    `json:"seat"`
+	HandId int    `json:"handId"`
+	Bet    int    `json:"bet"`
+}
+
+// HistoryCard is a card in the order it came out of the shoe
+type HistoryCard struct {
+	Order    int        `json:"order"`
+	Player   string     `json:"player"`
+	Seat     int        `json:"seat"`
+	HandId   int        `json:"handId"`
+	Card     cards.Card `json:"card"`
+	FaceDown bool       `json:"faceDown"`
+}
+
+type HistoryDecision struct {
+	Player string `json:"player"`
+	Seat   int    `json:"seat"`
+	HandId int    `json:"handId"`
+	Stage  string `json:"stage"`
+	Action string `json:"action"`
+}
+
+type HistoryOutcome struct {
+	Player  string `json:"player"`
+	Seat    int    `json:"seat"`
+	HandId  int    `json:"handId"`
+	Bet     int    `json:"bet"`
+	Outcome string `json:"outcome"`
+	Payout  int    `json:"payout"`
+	Cash    int    `json:"cash"`
+}
+
+// WithHandHistory writes a hand history line for every round played
+func WithHandHistory(output io.Writer) Option {
+	return func(g *Game) error {
+		g.historyOutput = output
+		g.Subscribe(g.RecordHistory)
+		return nil
+	}
+}
+
+// RecordHistory builds the hand history of the round from the game's
+// events and writes it out when the round ends
+func (g *Game) RecordHistory(e Event) {
+
+	if e.Type == EventRoundStarted {
+		g.history = &HandHistory{
+			ShoeId:    g.ShoeId,
+			Seed:      g.Seed,
+			Round:     e.Round,
+			Variant:   VariantMap[g.Variant],
+			Decks:     g.DeckCount,
+			TiePush:   g.BlackjackTiePush,
+			SeatCount: g.SeatCount,
+		}
+		for _, player := range g.Players {
+			g.history.Seats = append(g.history.Seats, HistorySeat{Player: player.Name, Seat: player.Seat, Spots: player.SpotCount(), Cash: player.Cash, Record: player.Record})
+		}
+		return
+	}
+
+	h := g.history
+	if h == nil {
+		return
+	}
+
+	switch e.Type {
+	case EventBetPlaced:
+		h.Bets = append(h.Bets, HistoryBet{Player: e.Player, Seat: e.Seat, HandId: e.HandId, Bet: e.Bet})
+	case EventCardDealt:
+		h.Cards = append(h.Cards, HistoryCard{Order: len(h.Cards) + 1, Player: e.Player, Seat: e.Seat, HandId: e.HandId, Card: e.Card, FaceDown: e.FaceDown})
+	case EventActionTaken:
+		h.Decisions = append(h.Decisions, HistoryDecision{Player: e.Player, Seat: e.Seat, HandId: e.HandId, Stage: e.Stage.String(), Action: e.Action.String()})
+	case EventHandSettled:
+		h.Outcomes = append(h.Outcomes, HistoryOutcome{Player: e.Player, Seat: e.Seat, HandId: e.HandId, Bet: e.Bet, Outcome: e.Outcome.String(), Payout: e.Payout, Cash: e.Cash})
+	case EventRoundEnded:
+		for i, seat := range h.Seats {
+			for _, player := range g.Players {
+				if player.Name == seat.Player && player.Seat == seat.Seat {
+					h.Seats[i].Record = player.Record
+				}
+			}
+		}
+		if len(h.Cards) > 0 {
+			h.DealerHand = g.Dealer.Hands[0].Cards
+		}
+
+		err := WriteHandHistory(g.historyOutput, *h)
+		if err != nil {
+			fmt.Fprintln(g.output, err)
+		}
+		g.history = nil
+	}
+}
+
+func WriteHandHistory(output io.Writer, h HandHistory) error {
+
+	line, err := json.Marshal(h)
+	if err != nil {
+		return fmt.Errorf("unable to encode hand history, %s", err)
+	}
+
+	_, err = fmt.Fprintln(output, string(line))
+	if err != nil {
+		return fmt.Errorf("unable to write hand history, %s", err)
+	}
+
+	return nil
+}
+
+// ReadHandHistory reads every round of a hand history file
+func ReadHandHistory(input io.Reader) ([]HandHistory, error) {
+
+	rounds := []HandHistory{}
+
+	scanner := bufio.NewScanner(input)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		h := HandHistory{}
+		err := json.Unmarshal(scanner.Bytes(), &h)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode hand history line %d, %s", len(rounds)+1, err)
+		}
+		rounds = append(rounds, h)
+	}
+
+	err := scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read hand history, %s", err)
+	}
+
+	return rounds, nil
+}
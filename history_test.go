@@ -0,0 +1,116 @@
+package blackjack_test
+
+import (
+	"blackjack"
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/mbarley333/cards"
+)
+
+func TestHandHistory(t *testing.T) {
+	t.Parallel()
+
+	stack := []cards.Card{
+		{Rank: cards.Ten, Suit: cards.Club},
+		{Rank: cards.Seven, Suit: cards.Club},
+		{Rank: cards.Six, Suit: cards.Club},
+		{Rank: cards.King, Suit: cards.Spade},
+		{Rank: cards.Five, Suit: cards.Heart},
+	}
+
+	deck := cards.Deck{
+		Cards: stack,
+	}
+
+	output := &bytes.Buffer{}
+
+	g, err := blackjack.NewBlackjackGame(
+		blackjack.WithCustomDeck(deck),
+		blackjack.WithIncomingDeck(false),
+		blackjack.WithRendering(false),
+		blackjack.WithSeed(42),
+		blackjack.WithHandHistory(output),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := &blackjack.Player{
+		Name: "Hound",
+		Cash: 100,
+	}
+	g.AddPlayer(p)
+
+	g.StartRound()
+	g.PlaceBet(p, 10)
+	g.ApplyAction(p, blackjack.ActionHit)
+	g.ApplyAction(p, blackjack.ActionStand)
+
+	h := blackjack.HandHistory{}
+	err = json.Unmarshal(output.Bytes(), &h)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := blackjack.HandHistory{
+		ShoeId:  1,
+		Seed:    42,
+		Round:   1,
+		Variant: "Classic",
+		Decks:   6,
+		Seats: []blackjack.HistorySeat{
+			{Player: "Hound", Spots: 1, Cash: 100, Record: blackjack.Record{Win: 1, HandsPlayed: 1}},
+		},
+		Bets: []blackjack.HistoryBet{
+			{Player: "Hound", HandId: 1, Bet: 10},
+		},
+		Cards: []blackjack.HistoryCard{
+			{Order: 1, Player: "Hound", HandId: 1, Card: stack[0]},
+			{Order: 2, Player: "Dealer", HandId: 1, Card: stack[1], FaceDown: true},
+			{Order: 3, Player: "Hound", HandId: 1, Card: stack[2]},
+			{Order: 4, Player: "Dealer", HandId: 1, Card: stack[3]},
+			{Order: 5, Player: "Hound", HandId: 1, Card: stack[4]},
+		},
+		Decisions: []blackjack.HistoryDecision{
+			{Player: "Hound", HandId: 1, Stage: "Deciding", Action: "Hit"},
+			{Player: "Hound", HandId: 1, Stage: "Deciding", Action: "Stand"},
+		},
+		DealerHand: []cards.Card{stack[1], stack[3]},
+		Outcomes: []blackjack.HistoryOutcome{
+			{Player: "Hound", HandId: 1, Bet: 10, Outcome: "Win", Payout: 10, Cash: 110},
+		},
+	}
+	got := h
+
+	if !cmp.Equal(want, got) {
+		t.Fatal(cmp.Diff(want, got))
+	}
+}
+
+func TestSeedDealsSameShoe(t *testing.T) {
+	t.Parallel()
+
+	g1, err := blackjack.NewBlackjackGame(blackjack.WithSeed(7))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g2, err := blackjack.NewBlackjackGame(blackjack.WithSeed(7))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := g1.Shoe.Cards
+	got := g2.Shoe.Cards
+
+	if !cmp.Equal(want, got) {
+		t.Fatal(cmp.Diff(want, got))
+	}
+
+	if g1.IncomingDeckPosition != g2.IncomingDeckPosition {
+		t.Fatalf("want: %d, got: %d", g1.IncomingDeckPosition, g2.IncomingDeckPosition)
+	}
+}
@@ -0,0 +1,449 @@
+package blackjack
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Wallet holds each player's bankroll while they are in the lobby.  A
+// player takes the whole bankroll to a table and brings back what is
+// left, so the bankroll can only be at one table at a time.  Each
+// bankroll is kept under the token it was opened with
+type Wallet struct {
+	mu       sync.Mutex
+	balances map[string]int
+	tables   map[string]string
+	tokens   map[string]string
+}
+
+func NewWallet() *Wallet {
+	return &Wallet{
+		balances: map[string]int{},
+		tables:   map[string]string{},
+		tokens:   map[string]string{},
+	}
+}
+
+// Open starts a bankroll for a new player under token.  A returning
+// player keeps the bankroll they have, as long as they have its token
+func (w *Wallet) Open(name, token string, cash int) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, ok := w.balances[name]; !ok {
+		w.balances[name] = cash
+		w.tokens[name] = token
+		return nil
+	}
+
+	if token == "" || w.tokens[name] != token {
+		return fmt.Errorf("that is not the token for %s", name)
+	}
+	return nil
+}
+
+// Opened reports whether there is a bankroll under the name
+func (w *Wallet) Opened(name string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	_, ok := w.balances[name]
+	return ok
+}
+
+func (w *Wallet) Balance(name string) int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.balances[name]
+}
+
+// Withdraw takes the bankroll to the table
+func (w *Wallet) Withdraw(name, table string) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if at, ok := w.tables[name]; ok {
+		return 0, fmt.Errorf("%s is already playing at %s", name, at)
+	}
+
+	cash := w.balances[name]
+	w.balances[name] = 0
+	w.tables[name] = table
+	return cash, nil
+}
+
+// Deposit brings the bankroll back from the table
+func (w *Wallet) Deposit(name string, cash int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.balances[name] += cash
+	delete(w.tables, name)
+}
+
+// LobbyTable is a table in the lobby, run by its own server
+type LobbyTable struct {
+	Name   string
+	Server *Server
+}
+
+// Lobby hosts several tables over one TCP listener.  Players pick a
+// table from the lobby, play there and come back to the lobby when they
+// leave, keeping their bankroll as they move between tables.  Every
+// table plays its rounds in its own goroutine
+type Lobby struct {
+	Tables       []*LobbyTable
+	Wallet       *Wallet
+	StartingCash int
+	listener     net.Listener
+	mu           sync.Mutex
+	clients      map[string]*Client
+	done         chan struct{}
+	closeOnce    sync.Once
+}
+
+// NewLobby creates a lobby on listener with a table for each of rules.
+// Options are applied to every table, before the table's own rules
+func NewLobby(listener net.Listener, rules []TableRules, opts ...Option) (*Lobby, error) {
+
+	l := &Lobby{
+		Wallet:       NewWallet(),
+		StartingCash: 100,
+		listener:     listener,
+		clients:      map[string]*Client{},
+		done:         make(chan struct{}),
+	}
+
+	for i, r := range rules {
+		ruleOpts, err := r.Options()
+		if err != nil {
+			return nil, fmt.Errorf("table %d, %s", i+1, err)
+		}
+
+		s, err := NewServer(nil, append(append([]Option{}, opts...), ruleOpts...)...)
+		if err != nil {
+			return nil, fmt.Errorf("table %d, %s", i+1, err)
+		}
+
+		l.Tables = append(l.Tables, &LobbyTable{Name: "Table " + strconv.Itoa(i+1), Server: s})
+	}
+
+	return l, nil
+}
+
+// Run plays every table and takes players into the lobby until the
+// lobby is closed
+func (l *Lobby) Run() error {
+
+	errs := make(chan error, len(l.Tables))
+	for _, t := range l.Tables {
+		go func(t *LobbyTable) {
+			errs <- t.Server.Run()
+		}(t)
+	}
+
+	go l.accept()
+
+	select {
+	case <-l.done:
+		return nil
+	case err := <-errs:
+		l.Close()
+		return err
+	}
+}
+
+// Close closes every table and drops every connection
+func (l *Lobby) Close() error {
+
+	l.closeOnce.Do(func() {
+		close(l.done)
+	})
+	err := l.listener.Close()
+
+	for _, t := range l.Tables {
+		t.Server.Close()
+	}
+
+	l.mu.Lock()
+	for _, c := range l.clients {
+		c.mu.Lock()
+		if c.conn != nil {
+			c.conn.Close()
+		}
+		c.mu.Unlock()
+	}
+	l.mu.Unlock()
+
+	return err
+}
+
+func (l *Lobby) Addr() net.Addr {
+	return l.listener.Addr()
+}
+
+func (l *Lobby) accept() {
+	for {
+		conn, err := l.listener.Accept()
+		if err != nil {
+			return
+		}
+		go l.handle(conn)
+	}
+}
+
+// handle greets a connection and passes its lines on until it drops.
+// A new player is given a token.  With it a player who drops at a table
+// can connect again under the same name and pick up where they left
+// off, and one who comes back to the lobby gets their bankroll back
+func (l *Lobby) handle(conn net.Conn) {
+
+	scanner := bufio.NewScanner(conn)
+	ask := func(question string) (string, bool) {
+		fmt.Fprintf(conn, "%s %s\n", ServerQuestion, question)
+		if !scanner.Scan() {
+			return "", false
+		}
+		return strings.TrimSpace(scanner.Text()), true
+	}
+
+	var c *Client
+	for c == nil {
+		name, ok := ask("Enter your name: ")
+		if !ok {
+			conn.Close()
+			return
+		}
+		if name == "" {
+			continue
+		}
+
+		l.mu.Lock()
+		existing, found := l.clients[name]
+		connected := found && existing.connected()
+		l.mu.Unlock()
+
+		if connected {
+			fmt.Fprintf(conn, "%s %s is already in the lobby, choose another name\n", ServerMessage, name)
+			continue
+		}
+
+		token := ""
+		if found || l.Wallet.Opened(name) {
+			token, ok = ask(fmt.Sprintf("Enter the token for %s: ", name))
+			if !ok {
+				conn.Close()
+				return
+			}
+		}
+
+		if found {
+			// the player is only taken back by one connection with the token
+			l.mu.Lock()
+			if l.clients[name] == existing && !existing.connected() && existing.token == token {
+				c = existing
+				c.attach(conn)
+			}
+			l.mu.Unlock()
+
+			if c == nil {
+				fmt.Fprintf(conn, "%s That is not the token for %s, choose another name\n", ServerMessage, name)
+				continue
+			}
+			c.message("Welcome back " + name)
+			break
+		}
+
+		fresh := token == ""
+		if fresh {
+			var err error
+			token, err = newToken()
+			if err != nil {
+				fmt.Fprintf(conn, "%s Unable to open a bankroll, %s\n", ServerGoodbye, err)
+				conn.Close()
+				return
+			}
+		}
+
+		err := l.Wallet.Open(name, token, l.StartingCash)
+		if err != nil {
+			fmt.Fprintf(conn, "%s That is not the token for %s, choose another name\n", ServerMessage, name)
+			continue
+		}
+		if fresh {
+			fmt.Fprintf(conn, "%s Your token is %s, keep it to come back to your bankroll or your seat\n", ServerMessage, token)
+		}
+
+		answer, ok := ask(fmt.Sprintf("%s enter number of spots to play (1-%d) [1]: ", name, MaxSpots))
+		if !ok {
+			conn.Close()
+			return
+		}
+		spots, err := strconv.Atoi(answer)
+		if err != nil || spots < 1 || spots > MaxSpots {
+			spots = 1
+		}
+
+		joining := &Client{
+			token:       token,
+			lines:       make(chan string, 16),
+			reconnected: make(chan struct{}, 1),
+		}
+		joining.Player = HumanPlayer(name, spots)
+		joining.Player.Output = joining
+		joining.Player.Input = joining
+
+		l.mu.Lock()
+		_, taken := l.clients[name]
+		if !taken {
+			l.clients[name] = joining
+			joining.attach(conn)
+		}
+		l.mu.Unlock()
+
+		if taken {
+			fmt.Fprintf(conn, "%s %s is already in the lobby, choose another name\n", ServerMessage, name)
+			continue
+		}
+		c = joining
+
+		go l.visit(c)
+	}
+
+	for scanner.Scan() {
+		c.queue(scanner.Text())
+	}
+
+	c.detach(conn)
+}
+
+// visit keeps the player in the lobby, sending them to the tables they
+// choose, until they quit or drop while in the lobby
+func (l *Lobby) visit(c *Client) {
+
+	name := c.Player.Name
+	reader := BufferedReader(c.Player.Input)
+
+	for {
+		if l.leave(c) {
+			return
+		}
+
+		l.Render(c, name)
+		fmt.Fprintf(c, "%s choose a table (1-%d) or (Q)uit [1]: ", name, len(l.Tables))
+		answer, _ := reader.ReadString('\n')
+		answer = strings.ToLower(strings.TrimSpace(answer))
+
+		if l.leave(c) {
+			return
+		}
+
+		if answer == "q" {
+			l.mu.Lock()
+			delete(l.clients, name)
+			l.mu.Unlock()
+			c.goodbye(fmt.Sprintf("Thanks for playing %s, you leave with $%d", name, l.Wallet.Balance(name)))
+			return
+		}
+
+		choice := 1
+		if answer != "" {
+			var err error
+			choice, err = strconv.Atoi(answer)
+			if err != nil || choice < 1 || choice > len(l.Tables) {
+				fmt.Fprintf(c, "There is no table %s\n", answer)
+				continue
+			}
+		}
+
+		l.play(c, l.Tables[choice-1])
+	}
+}
+
+// play takes the player's bankroll to the table and brings it back
+// once they leave
+func (l *Lobby) play(c *Client, t *LobbyTable) {
+
+	name := c.Player.Name
+	cash, err := l.Wallet.Withdraw(name, t.Name)
+	if err != nil {
+		fmt.Fprintln(c, err)
+		return
+	}
+
+	min := t.Server.Status().Limits.Min
+	if min < 1 {
+		min = 1
+	}
+	if cash < min {
+		l.Wallet.Deposit(name, cash)
+		fmt.Fprintf(c, "%s needs at least $%d to play at %s\n", name, min, t.Name)
+		return
+	}
+
+	c.Player.Cash = cash
+	left, err := t.Server.Seat(c)
+	if err != nil {
+		l.Wallet.Deposit(name, cash)
+		fmt.Fprintln(c, err)
+		return
+	}
+
+	select {
+	case <-left:
+	case <-l.done:
+		return
+	}
+
+	c.mu.Lock()
+	c.server = nil
+	c.mu.Unlock()
+	l.Wallet.Deposit(name, c.Player.Cash)
+	fmt.Fprintf(c, "%s is back in the lobby with $%d\n", name, l.Wallet.Balance(name))
+}
+
+// leave ends the visit of a player who has dropped in the lobby.  Their
+// bankroll stays in the wallet for when they come back
+func (l *Lobby) leave(c *Client) bool {
+
+	select {
+	case <-l.done:
+		return true
+	default:
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if c.connected() {
+		return false
+	}
+	delete(l.clients, c.Player.Name)
+	return true
+}
+
+// Render lists the tables with their rules, limits, seats taken and
+// how far into the shoe they are
+func (l *Lobby) Render(output io.Writer, name string) {
+
+	fmt.Fprintln(output, "************** Lobby **************")
+	for i, t := range l.Tables {
+		status := t.Server.Status()
+		min := status.Limits.Min
+		if min < 1 {
+			min = 1
+		}
+		limit := "no limit"
+		if status.Limits.Max > 0 {
+			limit = "$" + strconv.Itoa(status.Limits.Max)
+		}
+		fmt.Fprintf(output, "%d. %s: %s, %d decks, bets $%d to %s, %d of %d seats taken, %.0f%% of the shoe dealt\n",
+			i+1, t.Name, status.Variant, status.Decks, min, limit, status.Seated, status.Seats, status.Penetration*100)
+	}
+	fmt.Fprintf(output, "%s has $%d\n", name, l.Wallet.Balance(name))
+}
@@ -0,0 +1,178 @@
+package blackjack_test
+
+import (
+	"blackjack"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mbarley333/cards"
+)
+
+func newTestLobby(t *testing.T) *blackjack.Lobby {
+
+	stack := []cards.Card{
+		{Rank: cards.Ten, Suit: cards.Club},
+		{Rank: cards.Seven, Suit: cards.Club},
+		{Rank: cards.Nine, Suit: cards.Club},
+		{Rank: cards.King, Suit: cards.Spade},
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rules := []blackjack.TableRules{
+		{},
+		{Variant: "freebet", Decks: 2, Seats: 3, MinBet: 5, MaxBet: 50},
+	}
+
+	l, err := blackjack.NewLobby(listener, rules,
+		blackjack.WithCustomDeck(cards.Deck{Cards: stack}),
+		blackjack.WithIncomingDeck(false),
+		blackjack.WithRendering(false),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, table := range l.Tables {
+		table.Server.ReconnectWait = 5 * time.Second
+	}
+
+	done := make(chan error)
+	go func() {
+		done <- l.Run()
+	}()
+	t.Cleanup(func() {
+		l.Close()
+		<-done
+	})
+
+	return l
+}
+
+func TestLobbyMovesBankrollBetweenTables(t *testing.T) {
+	t.Parallel()
+
+	l := newTestLobby(t)
+	c := dial(t, l.Addr())
+
+	c.answer(t, "Enter your name", "Optimus")
+	c.answer(t, "number of spots", "1")
+	lines := c.answer(t, "choose a table (1-2)", "2")
+
+	lobby := strings.Join(lines, "\n")
+	for _, want := range []string{
+		"1. Table 1: Classic, 6 decks, bets $1 to no limit, 0 of 7 seats taken",
+		"2. Table 2: Free Bet, 2 decks, bets $5 to $50, 0 of 3 seats taken",
+		"Optimus has $100",
+	} {
+		if !strings.Contains(lobby, want) {
+			t.Fatalf("want %q in the lobby, got: %q", want, lines)
+		}
+	}
+
+	c.answer(t, "choose a seat", "")
+	c.answer(t, "(B)et or (Q)uit", "b")
+	c.answer(t, "place your bet ($5 to $50", "10")
+	c.answer(t, "(S)tand", "s")
+	c.answer(t, "(B)et or (Q)uit", "q")
+
+	lines = c.answer(t, "choose a table (1-2)", "q")
+	if !strings.Contains(strings.Join(lines, "\n"), "Optimus is back in the lobby with $110") {
+		t.Fatalf("want the winnings back in the lobby, got: %q", lines)
+	}
+
+	lines = c.next(t)
+	last := lines[len(lines)-1]
+	if !strings.HasPrefix(last, blackjack.ServerGoodbye) || !strings.Contains(last, "you leave with $110") {
+		t.Fatalf("want goodbye with the bankroll, got: %q", lines)
+	}
+
+	want := 110
+	got := l.Wallet.Balance("Optimus")
+	if want != got {
+		t.Fatalf("want wallet balance %d, got %d", want, got)
+	}
+}
+
+func TestWallet(t *testing.T) {
+	t.Parallel()
+
+	w := blackjack.NewWallet()
+	err := w.Open("Ratchet", "wrench", 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = w.Open("Ratchet", "wrench", 500)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = w.Open("Ratchet", "spanner", 500)
+	if err == nil {
+		t.Fatal("want an error opening the bankroll without its token")
+	}
+
+	cash, err := w.Withdraw("Ratchet", "Table 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cash != 100 {
+		t.Fatalf("want the whole bankroll of 100 taken to the table, got %d", cash)
+	}
+
+	_, err = w.Withdraw("Ratchet", "Table 2")
+	if err == nil {
+		t.Fatal("want an error taking the bankroll to a second table")
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.Deposit("Ratchet", 10)
+		}()
+	}
+	wg.Wait()
+
+	want := 100
+	got := w.Balance("Ratchet")
+	if want != got {
+		t.Fatalf("want balance %d, got %d", want, got)
+	}
+}
+
+func TestLobbyBankrollNeedsToken(t *testing.T) {
+	t.Parallel()
+
+	l := newTestLobby(t)
+	c := dial(t, l.Addr())
+
+	c.answer(t, "Enter your name", "Optimus")
+	token := reconnectToken(t, c.answer(t, "number of spots", "1"))
+	c.answer(t, "choose a table (1-2)", "q")
+	c.next(t)
+	c.conn.Close()
+
+	c = dial(t, l.Addr())
+	c.answer(t, "Enter your name", "Optimus")
+	c.answer(t, "token for Optimus", "megatron")
+	lines := c.answer(t, "Enter your name", "Optimus")
+	if !strings.Contains(strings.Join(lines, "\n"), "not the token for Optimus") {
+		t.Fatalf("want the bankroll kept from a wrong token, got: %q", lines)
+	}
+
+	c.answer(t, "token for Optimus", token)
+	lines = c.answer(t, "number of spots", "1")
+	if strings.Contains(strings.Join(lines, "\n"), "token is") {
+		t.Fatalf("want no new token for a returning player, got: %q", lines)
+	}
+	lines = c.next(t)
+	if !strings.Contains(strings.Join(lines, "\n"), "Optimus has $100") {
+		t.Fatalf("want the bankroll back with the token, got: %q", lines)
+	}
+}
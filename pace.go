@@ -0,0 +1,100 @@
+package blackjack
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// Pace is how quickly cards are dealt on the terminal
+type Pace int
+
+const (
+	PaceDefault Pace = iota
+	PaceNormal
+	PaceFast
+	PaceInstant
+	PaceStep
+)
+
+var PaceMap = map[Pace]string{
+	PaceDefault: "Default",
+	PaceNormal:  "Normal",
+	PaceFast:    "Fast",
+	PaceInstant: "Instant",
+	PaceStep:    "Step",
+}
+
+var PaceInputMap = map[string]Pace{
+	"default": PaceDefault,
+	"normal":  PaceNormal,
+	"fast":    PaceFast,
+	"instant": PaceInstant,
+	"step":    PaceStep,
+}
+
+func (p Pace) String() string {
+	return PaceMap[p]
+}
+
+// PaceDelayMap is the pause after each card dealt in the opening deal
+// and after each card the dealer draws
+var PaceDelayMap = map[Pace]map[Stage]time.Duration{
+	PaceNormal: {
+		StageOpeningDeal: 750 * time.Millisecond,
+		StageDealerPlay:  2 * time.Second,
+	},
+	PaceFast: {
+		StageOpeningDeal: 200 * time.Millisecond,
+		StageDealerPlay:  500 * time.Millisecond,
+	},
+}
+
+// WithPace sets how quickly cards are dealt.  The default is the normal
+// pace while a human is playing and instant once only AI players and
+// bots are left
+func WithPace(pace Pace) Option {
+	return func(g *Game) error {
+		_, ok := PaceMap[pace]
+		if !ok {
+			return fmt.Errorf("unknown pace %d", pace)
+		}
+		g.Pace = pace
+		return nil
+	}
+}
+
+// CurrentPace is the pace cards are being dealt at
+func (g *Game) CurrentPace() Pace {
+
+	if g.Pace != PaceDefault {
+		return g.Pace
+	}
+
+	for _, player := range g.Players {
+		if player.Type == PlayerTypeHuman {
+			return PaceNormal
+		}
+	}
+
+	return PaceInstant
+}
+
+// pause holds the deal after a card so it can be followed.  Stepping
+// through waits for enter instead
+func (g *Game) pause(stage Stage) {
+
+	pace := g.CurrentPace()
+	if pace != PaceStep {
+		time.Sleep(PaceDelayMap[pace][stage])
+		return
+	}
+
+	var input io.Reader = g.input
+	if g.DecisionTimeout > 0 {
+		input = g.contextInput(input)
+	}
+
+	fmt.Fprint(g.output, "Press enter to continue")
+	BufferedReader(input).ReadString('\n')
+}
@@ -0,0 +1,103 @@
+package blackjack_test
+
+import (
+	"blackjack"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mbarley333/cards"
+)
+
+func TestPaceDefault(t *testing.T) {
+	t.Parallel()
+
+	g, err := blackjack.NewBlackjackGame(blackjack.WithOutput(io.Discard))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g.AddPlayer(&blackjack.Player{Name: "Optimus", Type: blackjack.PlayerTypeAiBasic})
+	if g.CurrentPace() != blackjack.PaceInstant {
+		t.Fatalf("want instant without human players, got %s", g.CurrentPace())
+	}
+
+	g.AddPlayer(blackjack.HumanPlayer("Ironhide", 1))
+	if g.CurrentPace() != blackjack.PaceNormal {
+		t.Fatalf("want normal with a human player, got %s", g.CurrentPace())
+	}
+
+	blackjack.WithPace(blackjack.PaceFast)(g)
+	if g.CurrentPace() != blackjack.PaceFast {
+		t.Fatalf("want the pace chosen, got %s", g.CurrentPace())
+	}
+}
+
+// newPacedGame deals an AI player 19 against a dealer 17
+func newPacedGame(t *testing.T, opts ...blackjack.Option) (*blackjack.Game, *strings.Builder) {
+
+	stack := []cards.Card{
+		{Rank: cards.Ten, Suit: cards.Club},
+		{Rank: cards.Seven, Suit: cards.Club},
+		{Rank: cards.Nine, Suit: cards.Club},
+		{Rank: cards.King, Suit: cards.Spade},
+	}
+
+	output := &strings.Builder{}
+	g, err := blackjack.NewBlackjackGame(append([]blackjack.Option{
+		blackjack.WithCustomDeck(cards.Deck{Cards: stack}),
+		blackjack.WithIncomingDeck(false),
+		blackjack.WithOutput(output),
+	}, opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g.AddPlayer(&blackjack.Player{
+		Name:           "Optimus",
+		Type:           blackjack.PlayerTypeAiStandOnly,
+		Cash:           100,
+		Bet:            blackjack.AiBet,
+		Decide:         blackjack.AiActionStandOnly,
+		AiRoundsToPlay: 1,
+	})
+
+	return g, output
+}
+
+func TestPaceInstantDoesNotWait(t *testing.T) {
+	t.Parallel()
+
+	g, _ := newPacedGame(t)
+
+	start := time.Now()
+	err := g.PlayRound()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if time.Since(start) > 500*time.Millisecond {
+		t.Fatalf("want an AI round dealt instantly, took %s", time.Since(start))
+	}
+}
+
+func TestPaceStepWaitsForEnter(t *testing.T) {
+	t.Parallel()
+
+	g, output := newPacedGame(t,
+		blackjack.WithPace(blackjack.PaceStep),
+		blackjack.WithInput(strings.NewReader(strings.Repeat("\n", 4))),
+	)
+
+	err := g.PlayRound()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := 4
+	got := strings.Count(output.String(), "Press enter to continue")
+	if want != got {
+		t.Fatalf("want a step for each card of the opening deal, got %d", got)
+	}
+}
@@ -0,0 +1,186 @@
+package blackjack
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mbarley333/cards"
+)
+
+// CountPractice hides the count from human players and checks their
+// own count between rounds, at random and whenever the shoe is
+// shuffled.  With Cheat set the count can still be shown with "c"
+type CountPractice struct {
+	Cheat       bool
+	CheckChance float64
+	Scores      map[string]*CountScore
+	count       CardCounter
+	seen        int
+	hidden      []cards.Card
+	shuffled    *CardCounter
+	source      *Source
+	random      *rand.Rand
+}
+
+// CountScore is a player's record of count checks
+type CountScore struct {
+	Checks       int
+	RunningRight int
+	TrueRight    int
+}
+
+// WithCountPractice turns on count practice.  A count check comes
+// after about one round in four
+func WithCountPractice(cheat bool) Option {
+	return func(g *Game) error {
+		g.Practice = &CountPractice{
+			Cheat:       cheat,
+			CheckChance: 0.25,
+			Scores:      map[string]*CountScore{},
+		}
+		g.Subscribe(g.TrackPractice)
+		return nil
+	}
+}
+
+// CountFor is the count a player is allowed to see
+func (g *Game) CountFor(player *Player) CardCounter {
+	if g.Practice != nil && !g.Practice.Cheat && player != nil && player.Type == PlayerTypeHuman {
+		return CardCounter{Hidden: true}
+	}
+	return g.CardCounter
+}
+
+func (p *CountPractice) countCard(g *Game, card cards.Card) {
+	p.seen++
+	p.count.Count, p.count.TrueCount = g.CountCards(card, p.count.Count, p.seen, g.DeckCount)
+}
+
+// TrackPractice keeps the count of the cards the players have seen.
+// Face down cards count once they are turned over, or at the end of
+// the round when every hand is shown
+func (g *Game) TrackPractice(e Event) {
+
+	p := g.Practice
+
+	switch e.Type {
+	case EventCardDealt:
+		if e.FaceDown {
+			p.hidden = append(p.hidden, e.Card)
+		} else {
+			p.countCard(g, e.Card)
+		}
+
+	case EventHoleCardRevealed:
+		for i, card := range p.hidden {
+			if card == e.Card {
+				p.hidden = append(p.hidden[:i], p.hidden[i+1:]...)
+				p.countCard(g, card)
+				break
+			}
+		}
+
+	case EventShoeShuffled:
+		count := p.count
+		p.shuffled = &count
+		p.count = CardCounter{}
+		p.seen = 0
+		p.hidden = nil
+
+	case EventRoundEnded:
+		for _, card := range p.hidden {
+			p.countCard(g, card)
+		}
+		p.hidden = nil
+	}
+}
+
+// CountCheck asks each human player for the count after a shuffle, and
+// at random between rounds, and scores the answers
+func (g *Game) CountCheck() {
+
+	p := g.Practice
+	if p == nil {
+		return
+	}
+
+	if p.shuffled != nil {
+		fmt.Fprintln(g.output, "*** Count check: the shoe was shuffled.  What was the count before the shuffle? ***")
+		g.askCount(*p.shuffled)
+		p.shuffled = nil
+	} else if p.chance(g) < p.CheckChance {
+		fmt.Fprintln(g.output, "*** Count check ***")
+		g.askCount(p.count)
+	}
+}
+
+// chance draws from its own source seeded by the game, so that count
+// checks do not change how the shoe is shuffled
+func (p *CountPractice) chance(g *Game) float64 {
+	if p.random == nil {
+		p.source = NewSource(g.Seed)
+		p.random = rand.New(p.source)
+	}
+	return p.random.Float64()
+}
+
+func (g *Game) askCount(want CardCounter) {
+
+	for _, player := range g.Players {
+		if player.Type != PlayerTypeHuman || player.Action == ActionQuit {
+			continue
+		}
+
+		output := g.PlayerOutput(player)
+		reader := BufferedReader(g.PlayerInput(player))
+
+		fmt.Fprintf(output, "%s, running count? ", player.Name)
+		answer, _ := reader.ReadString('\n')
+		running, err := strconv.Atoi(strings.TrimSpace(answer))
+		runningRight := err == nil && running == want.Count
+
+		fmt.Fprintf(output, "%s, true count? ", player.Name)
+		answer, _ = reader.ReadString('\n')
+		trueCount, err := strconv.ParseFloat(strings.TrimSpace(answer), 64)
+		trueRight := err == nil && math.Abs(trueCount-want.TrueCount) <= 1
+
+		score, ok := g.Practice.Scores[player.Name]
+		if !ok {
+			score = &CountScore{}
+			g.Practice.Scores[player.Name] = score
+		}
+		score.Checks++
+		if runningRight {
+			score.RunningRight++
+		}
+		if trueRight {
+			score.TrueRight++
+		}
+
+		fmt.Fprintf(output, "%s running count %d, true count %.1f\n", countMark(runningRight && trueRight), want.Count, want.TrueCount)
+	}
+}
+
+// RenderPractice is the count check score of every player
+func (g *Game) RenderPractice(output io.Writer) {
+
+	if g.Practice == nil {
+		return
+	}
+
+	names := []string{}
+	for name := range g.Practice.Scores {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		score := g.Practice.Scores[name]
+		fmt.Fprintf(output, "%s count checks: running count right %d of %d, true count right %d of %d\n", name, score.RunningRight, score.Checks, score.TrueRight, score.Checks)
+	}
+}
@@ -0,0 +1,85 @@
+package blackjack_test
+
+import (
+	"blackjack"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/mbarley333/cards"
+)
+
+func TestCountPractice(t *testing.T) {
+	t.Parallel()
+
+	stack := []cards.Card{
+		{Rank: cards.Two, Suit: cards.Club},
+		{Rank: cards.Seven, Suit: cards.Club},
+		{Rank: cards.Three, Suit: cards.Club},
+		{Rank: cards.King, Suit: cards.Spade},
+		{Rank: cards.Five, Suit: cards.Heart},
+	}
+
+	g, err := blackjack.NewBlackjackGame(
+		blackjack.WithCustomDeck(cards.Deck{Cards: stack}),
+		blackjack.WithIncomingDeck(false),
+		blackjack.WithOutput(io.Discard),
+		blackjack.WithInput(strings.NewReader("2\n0\n")),
+		blackjack.WithRendering(false),
+		blackjack.WithCountPractice(false),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Practice.CheckChance = 1
+
+	p := &blackjack.Player{
+		Name: "Soundwave",
+		Type: blackjack.PlayerTypeHuman,
+		Cash: 100,
+	}
+	g.AddPlayer(p)
+
+	g.StartRound()
+	g.PlaceBet(p, 10)
+	g.ApplyAction(p, blackjack.ActionHit)
+	g.ApplyAction(p, blackjack.ActionStand)
+
+	if !g.CountFor(p).Hidden {
+		t.Fatal("want the count hidden from a human player in practice")
+	}
+
+	g.CountCheck()
+
+	want := map[string]*blackjack.CountScore{
+		"Soundwave": {Checks: 1, RunningRight: 1, TrueRight: 1},
+	}
+	got := g.Practice.Scores
+
+	if !cmp.Equal(want, got) {
+		t.Fatal(cmp.Diff(want, got))
+	}
+}
+
+func TestCountCheat(t *testing.T) {
+	t.Parallel()
+
+	g, err := blackjack.NewBlackjackGame(
+		blackjack.WithOutput(io.Discard),
+		blackjack.WithRendering(false),
+		blackjack.WithCountPractice(true),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g.CardCounter = blackjack.CardCounter{Count: 3, TrueCount: 0.5}
+
+	want := g.CardCounter
+	got := g.CountFor(&blackjack.Player{Name: "Soundwave", Type: blackjack.PlayerTypeHuman})
+
+	if !cmp.Equal(want, got) {
+		t.Fatal(cmp.Diff(want, got))
+	}
+}
@@ -0,0 +1,263 @@
+package blackjack
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Profile is a named player's lifetime statistics, kept between runs
+type Profile struct {
+	Name        string     `json:"name"`
+	Record      Record     `json:"record"`
+	Net         int        `json:"net"`
+	Blackjacks  int        `json:"blackjacks"`
+	BiggestWin  int        `json:"biggestWin"`
+	BiggestLoss int        `json:"biggestLoss"`
+	Doubles     int        `json:"doubles"`
+	DoublesWon  int        `json:"doublesWon"`
+	Splits      int        `json:"splits"`
+	SplitsWon   int        `json:"splitsWon"`
+	Accuracy    Accuracy   `json:"accuracy"`
+	Sessions    []*Session `json:"sessions"`
+}
+
+// Session is one sitting at the table.  Bankroll is the player's cash
+// when they sat down and after each round
+type Session struct {
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+	Record   Record    `json:"record"`
+	Net      int       `json:"net"`
+	Bankroll []int     `json:"bankroll"`
+}
+
+// ProfileStore keeps player profiles in a JSON file
+type ProfileStore struct {
+	Path     string
+	Profiles map[string]*Profile
+	doubled  map[string]bool
+	split    map[string]bool
+	splits   int
+}
+
+// OpenProfileStore reads the profiles in path.  A missing file is an
+// empty store
+func OpenProfileStore(path string) (*ProfileStore, error) {
+
+	store := &ProfileStore{
+		Path:     path,
+		Profiles: map[string]*Profile{},
+	}
+
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to open profiles, %s", err)
+	}
+	defer file.Close()
+
+	err = json.NewDecoder(file).Decode(&store.Profiles)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read profiles, %s", err)
+	}
+
+	return store, nil
+}
+
+// Profile returns the profile for name, creating it for a new player
+func (s *ProfileStore) Profile(name string) *Profile {
+
+	profile, ok := s.Profiles[name]
+	if !ok {
+		profile = &Profile{Name: name}
+		s.Profiles[name] = profile
+	}
+
+	return profile
+}
+
+// Save writes the store, replacing the file only once the write is complete
+func (s *ProfileStore) Save() error {
+
+	tmp := s.Path + ".tmp"
+	file, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("unable to create profiles, %s", err)
+	}
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	err = encoder.Encode(s.Profiles)
+	if err != nil {
+		file.Close()
+		os.Remove(tmp)
+		return fmt.Errorf("unable to save profiles, %s", err)
+	}
+
+	err = file.Close()
+	if err != nil {
+		return fmt.Errorf("unable to save profiles, %s", err)
+	}
+
+	return os.Rename(tmp, s.Path)
+}
+
+// WithProfiles keeps lifetime statistics for human players in the store
+func WithProfiles(store *ProfileStore) Option {
+	return func(g *Game) error {
+		g.Profiles = store
+		g.Subscribe(func(e Event) {
+			store.track(g, e)
+		})
+		return nil
+	}
+}
+
+// LoadProfile attaches the player's profile and starts a new session
+func (g *Game) LoadProfile(player *Player) {
+
+	if g.Profiles == nil {
+		return
+	}
+
+	player.Profile = g.Profiles.Profile(player.Name)
+	if player.Profile.Record.HandsPlayed > 0 {
+		fmt.Fprintln(g.output, player.Profile.Summary())
+	}
+
+	player.Profile.Sessions = append(player.Profile.Sessions, &Session{
+		Start:    time.Now(),
+		End:      time.Now(),
+		Bankroll: []int{player.Cash},
+	})
+}
+
+// Session is the profile's current session
+func (p *Profile) Session() *Session {
+	if len(p.Sessions) == 0 {
+		p.Sessions = append(p.Sessions, &Session{Start: time.Now()})
+	}
+	return p.Sessions[len(p.Sessions)-1]
+}
+
+// Summary is the welcome back line for a returning player
+func (p Profile) Summary() string {
+
+	str := []string{
+		"Welcome back ", p.Name,
+		".  Lifetime: ", strconv.Itoa(p.Record.HandsPlayed), " hands",
+		", won ", strconv.Itoa(p.Record.Win),
+		", lost ", strconv.Itoa(p.Record.Lose),
+		", tied ", strconv.Itoa(p.Record.Tie),
+		", net $", strconv.Itoa(p.Net),
+		" over ", strconv.Itoa(len(p.Sessions)), " sessions",
+	}
+
+	return strings.Join(str, "")
+}
+
+// Report is the lifetime statistics of the profile
+func (p Profile) Report(output io.Writer) {
+
+	fmt.Fprintf(output, "************** %s Lifetime Report **************\n", p.Name)
+	fmt.Fprintf(output, "Hands played: %d, won: %d, lost: %d, tied: %d\n", p.Record.HandsPlayed, p.Record.Win, p.Record.Lose, p.Record.Tie)
+	fmt.Fprintf(output, "Net result: $%d, biggest win: $%d, biggest loss: $%d\n", p.Net, p.BiggestWin, p.BiggestLoss)
+	fmt.Fprintf(output, "Blackjacks: %d, doubles won: %d of %d, splits won: %d of %d\n", p.Blackjacks, p.DoublesWon, p.Doubles, p.SplitsWon, p.Splits)
+	p.Accuracy.Report(output)
+	for i, session := range p.Sessions {
+		fmt.Fprintf(output, "Session %d on %s: %d hands, net $%d, bankroll %v\n", i+1, session.Start.Format("2006-01-02 15:04"), session.Record.HandsPlayed, session.Net, session.Bankroll)
+	}
+}
+
+func handKey(e Event) string {
+	return e.Player + "/" + strconv.Itoa(e.Seat) + "/" + strconv.Itoa(e.HandId)
+}
+
+// track adds each settled hand to the profile of its player.  Doubles
+// and splits are noted as the actions are taken.  The two cards dealt
+// after a split go to the split hands
+func (s *ProfileStore) track(g *Game, e Event) {
+
+	switch e.Type {
+	case EventRoundStarted:
+		s.doubled = map[string]bool{}
+		s.split = map[string]bool{}
+		s.splits = 0
+
+	case EventActionTaken:
+		if e.Action == ActionDoubleDown {
+			s.doubled[handKey(e)] = true
+		} else if e.Action == ActionSplit {
+			s.splits = 2
+		}
+
+	case EventCardDealt:
+		if s.splits > 0 {
+			s.split[handKey(e)] = true
+			s.splits--
+		}
+
+	case EventHandSettled:
+		player := g.findPlayer(e.Player, e.Seat)
+		if player == nil || player.Profile == nil {
+			return
+		}
+		profile := player.Profile
+		session := profile.Session()
+
+		profile.Record.Add(e.Outcome)
+		session.Record.Add(e.Outcome)
+		profile.Net += e.Payout
+		session.Net += e.Payout
+
+		if e.Outcome == OutcomeBlackjack {
+			profile.Blackjacks++
+		}
+		if e.Payout > profile.BiggestWin {
+			profile.BiggestWin = e.Payout
+		}
+		if -e.Payout > profile.BiggestLoss {
+			profile.BiggestLoss = -e.Payout
+		}
+
+		won := e.Outcome == OutcomeWin || e.Outcome == OutcomeBlackjack
+		if s.doubled[handKey(e)] {
+			profile.Doubles++
+			if won {
+				profile.DoublesWon++
+			}
+		}
+		if s.split[handKey(e)] {
+			profile.Splits++
+			if won {
+				profile.SplitsWon++
+			}
+		}
+
+	case EventRoundEnded:
+		saved := false
+		for _, player := range g.Players {
+			if player.Profile == nil {
+				continue
+			}
+			session := player.Profile.Session()
+			session.End = time.Now()
+			session.Bankroll = append(session.Bankroll, player.Cash)
+			saved = true
+		}
+
+		if saved {
+			err := s.Save()
+			if err != nil {
+				fmt.Fprintln(g.output, err)
+			}
+		}
+	}
+}
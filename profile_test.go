@@ -0,0 +1,92 @@
+package blackjack_test
+
+import (
+	"blackjack"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/mbarley333/cards"
+)
+
+func TestProfileLifetimeStats(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "profiles.json")
+
+	store, err := blackjack.OpenProfileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stack := []cards.Card{
+		{Rank: cards.Six, Suit: cards.Club},
+		{Rank: cards.Seven, Suit: cards.Club},
+		{Rank: cards.Five, Suit: cards.Club},
+		{Rank: cards.Ten, Suit: cards.Spade},
+		{Rank: cards.King, Suit: cards.Heart},
+	}
+
+	g, err := blackjack.NewBlackjackGame(
+		blackjack.WithCustomDeck(cards.Deck{Cards: stack}),
+		blackjack.WithIncomingDeck(false),
+		blackjack.WithOutput(io.Discard),
+		blackjack.WithRendering(false),
+		blackjack.WithProfiles(store),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := &blackjack.Player{
+		Name: "Blaster",
+		Type: blackjack.PlayerTypeHuman,
+		Cash: 100,
+	}
+	g.AddPlayer(p)
+	g.LoadProfile(p)
+
+	g.StartRound()
+	g.PlaceBet(p, 10)
+	g.ApplyAction(p, blackjack.ActionDoubleDown)
+
+	reopened, err := blackjack.OpenProfileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	profile := reopened.Profiles["Blaster"]
+	if profile == nil {
+		t.Fatal("want profile saved for Blaster")
+	}
+
+	want := blackjack.Profile{
+		Name:       "Blaster",
+		Record:     blackjack.Record{Win: 1, HandsPlayed: 1},
+		Net:        20,
+		BiggestWin: 20,
+		Doubles:    1,
+		DoublesWon: 1,
+		Accuracy: blackjack.Accuracy{
+			Decisions: 1,
+			Correct:   1,
+			Categories: map[string]*blackjack.CategoryAccuracy{
+				"hard": {Decisions: 1, Correct: 1},
+			},
+		},
+	}
+	got := *profile
+
+	if !cmp.Equal(want, got, cmpopts.IgnoreFields(blackjack.Profile{}, "Sessions")) {
+		t.Fatal(cmp.Diff(want, got))
+	}
+
+	wantBankroll := []int{100, 120}
+	gotBankroll := profile.Sessions[0].Bankroll
+
+	if !cmp.Equal(wantBankroll, gotBankroll) {
+		t.Fatal(cmp.Diff(wantBankroll, gotBankroll))
+	}
+}
@@ -0,0 +1,290 @@
+package blackjack
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/mbarley333/cards"
+)
+
+// Replay deals a recorded session again from its hand history.  The
+// recorded bets and decisions stand in for the players and every card
+// and outcome is checked against what the engine does now
+type Replay struct {
+	Rounds     []HandHistory
+	Mismatches []string
+	round      *HandHistory
+	decision   int
+	dealt      []HistoryCard
+	settled    []HistoryOutcome
+}
+
+func NewReplay(rounds []HandHistory) *Replay {
+	return &Replay{
+		Rounds: rounds,
+	}
+}
+
+// Run replays each round up to and including lastRound, or every round
+// when lastRound is 0.  The session is dealt from seed, or from the
+// recorded seed when seed is 0.  With step set it waits for enter
+// after each round.  Options are applied on top of the recorded rules
+func (r *Replay) Run(output io.Writer, input io.Reader, seed int64, lastRound int, step bool, opts ...Option) error {
+
+	if len(r.Rounds) == 0 {
+		return fmt.Errorf("hand history does not have any rounds")
+	}
+
+	first := r.Rounds[0]
+	if seed == 0 {
+		seed = first.Seed
+	}
+
+	variant, ok := variantByName(first.Variant)
+	if !ok {
+		return fmt.Errorf("unknown variant in hand history, %s", first.Variant)
+	}
+
+	opts = append([]Option{
+		WithOutput(output),
+		WithInput(input),
+		WithSeed(seed),
+		WithDeckCount(first.Decks),
+		WithVariant(variant),
+		WithBlackjackTiePush(first.TiePush),
+		WithSeatCount(first.SeatCount),
+		WithPace(PaceInstant),
+		WithSubscriber(r.check),
+	}, opts...)
+
+	g, err := NewBlackjackGame(opts...)
+	if err != nil {
+		return fmt.Errorf("unable to create game for replay, %s", err)
+	}
+
+	reader := BufferedReader(input)
+
+	for i := range r.Rounds {
+		if lastRound > 0 && r.Rounds[i].Round > lastRound {
+			break
+		}
+
+		r.round = &r.Rounds[i]
+		r.decision = 0
+		r.dealt = nil
+		r.settled = nil
+
+		err = r.seatPlayers(g)
+		if err != nil {
+			return err
+		}
+
+		err = g.PlayRound()
+		if err != nil {
+			return err
+		}
+
+		r.verify()
+
+		if step {
+			fmt.Fprintf(output, "Round %d replayed.  Press enter for the next round: ", r.round.Round)
+			reader.ReadString('\n')
+		}
+	}
+
+	return nil
+}
+
+// seatPlayers brings the table in line with the seats of the round
+// about to be replayed
+func (r *Replay) seatPlayers(g *Game) error {
+
+	for _, player := range g.Players {
+		if !r.isSeated(player.Name, player.Seat) {
+			player.Action = ActionQuit
+		}
+	}
+	g.Players = g.RemoveQuitPlayers()
+
+	for _, seat := range r.round.Seats {
+		if g.findPlayer(seat.Player, seat.Seat) != nil {
+			continue
+		}
+
+		player := &Player{
+			Name:   seat.Player,
+			Cash:   seat.Cash,
+			Spots:  seat.Spots,
+			Bet:    r.Bet,
+			Decide: r.Decide,
+			Switch: r.Switch,
+		}
+
+		var err error
+		if seat.Seat > 0 {
+			err = g.SitPlayer(player, seat.Seat)
+		} else {
+			err = g.AddPlayer(player)
+		}
+		if err != nil {
+			return fmt.Errorf("unable to seat %s for round %d, %s", seat.Player, r.round.Round, err)
+		}
+	}
+
+	return nil
+}
+
+func (r *Replay) isSeated(name string, seat int) bool {
+	for _, s := range r.round.Seats {
+		if s.Player == name && s.Seat == seat {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *Game) findPlayer(name string, seat int) *Player {
+	for _, player := range g.Players {
+		if player.Name == name && player.Seat == seat {
+			return player
+		}
+	}
+	return nil
+}
+
+// next takes the recorded decision for the player if it is the next
+// one in the round
+func (r *Replay) next(player *Player, stage Stage) (Action, bool) {
+
+	if r.decision >= len(r.round.Decisions) {
+		return None, false
+	}
+
+	d := r.round.Decisions[r.decision]
+	if d.Player != player.Name || d.Seat != player.Seat || d.Stage != stage.String() {
+		return None, false
+	}
+	r.decision++
+
+	return actionByName(d.Action), true
+}
+
+// Bet places the recorded bets of the active player
+func (r *Replay) Bet(g *Game) error {
+
+	player := g.ActivePlayer
+
+	if r.decision < len(r.round.Decisions) && r.round.Decisions[r.decision].Action == ActionQuit.String() {
+		action, ok := r.next(player, StageBetting)
+		if ok {
+			player.Action = action
+			fmt.Fprintf(g.output, "%s quits\n", player.Name)
+			return nil
+		}
+	}
+
+	player.Action = ActionBet
+	for _, index := range player.SpotIndexes() {
+		hand := player.Hands[index]
+		for _, bet := range r.round.Bets {
+			if bet.Player == player.Name && bet.Seat == player.Seat && bet.HandId == hand.Id {
+				player.CurrentBet = bet.Bet
+				player.PlaceBet(index, bet.Bet)
+				fmt.Fprintf(g.output, "%s%s bets $%d\n", player.Name, player.SpotLabel(hand), bet.Bet)
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// Switch chooses the recorded switch or keep
+func (r *Replay) Switch(g *Game) error {
+
+	player := g.ActivePlayer
+
+	action, ok := r.next(player, StageSwitching)
+	if !ok {
+		r.mismatch("%s has no recorded switch decision", player.Name)
+		action = ActionKeep
+	}
+	player.Action = action
+
+	return nil
+}
+
+// Decide plays the recorded action for the hand
+func (r *Replay) Decide(output io.Writer, input io.Reader, player *Player, dealerCard cards.Card, index int, c CardCounter, stage Stage) Action {
+
+	action, ok := r.next(player, stage)
+	if !ok {
+		r.mismatch("%s has no recorded decision for hand #%d", player.Name, player.Hands[index].Id)
+		action = ActionStand
+	}
+	fmt.Fprintf(output, "%s chooses to %s\n", player.Name, action)
+
+	return action
+}
+
+func (r *Replay) check(e Event) {
+	switch e.Type {
+	case EventCardDealt:
+		r.dealt = append(r.dealt, HistoryCard{Order: len(r.dealt) + 1, Player: e.Player, Seat: e.Seat, HandId: e.HandId, Card: e.Card, FaceDown: e.FaceDown})
+	case EventHandSettled:
+		r.settled = append(r.settled, HistoryOutcome{Player: e.Player, Seat: e.Seat, HandId: e.HandId, Bet: e.Bet, Outcome: e.Outcome.String(), Payout: e.Payout, Cash: e.Cash})
+	}
+}
+
+// verify compares the cards and outcomes of the replayed round with
+// the recording
+func (r *Replay) verify() {
+
+	for i, card := range r.round.Cards {
+		if i >= len(r.dealt) {
+			r.mismatch("card %d was not dealt, recorded %s to %s", card.Order, card.Card, card.Player)
+			break
+		}
+		if r.dealt[i] != card {
+			r.mismatch("card %d went %s to %s, recorded %s to %s", card.Order, r.dealt[i].Card, r.dealt[i].Player, card.Card, card.Player)
+			break
+		}
+	}
+
+	if len(r.settled) != len(r.round.Outcomes) {
+		r.mismatch("%d hands settled, recorded %d", len(r.settled), len(r.round.Outcomes))
+		return
+	}
+
+	for i, recorded := range r.round.Outcomes {
+		replayed := r.settled[i]
+		if replayed != recorded {
+			r.mismatch("%s hand #%d was %s paying $%d with $%d cash, recorded %s paying $%d with $%d cash",
+				replayed.Player, replayed.HandId, replayed.Outcome, replayed.Payout, replayed.Cash,
+				recorded.Outcome, recorded.Payout, recorded.Cash)
+		}
+	}
+}
+
+func (r *Replay) mismatch(format string, a ...interface{}) {
+	message := fmt.Sprintf("round %d: ", r.round.Round) + fmt.Sprintf(format, a...)
+	r.Mismatches = append(r.Mismatches, message)
+}
+
+func actionByName(name string) Action {
+	for action, s := range ActionStringMap {
+		if s == name {
+			return action
+		}
+	}
+	return None
+}
+
+func variantByName(name string) (Variant, bool) {
+	for variant, s := range VariantMap {
+		if s == name {
+			return variant, true
+		}
+	}
+	return VariantClassic, false
+}
@@ -0,0 +1,115 @@
+package blackjack_test
+
+import (
+	"blackjack"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func recordSession(t *testing.T) []blackjack.HandHistory {
+
+	history := &bytes.Buffer{}
+
+	g, err := blackjack.NewBlackjackGame(
+		blackjack.WithOutput(io.Discard),
+		blackjack.WithRendering(false),
+		blackjack.WithSeed(5),
+		blackjack.WithSeatCount(7),
+		blackjack.WithHandHistory(history),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"Jazz", "Prowl"} {
+		g.AddPlayer(&blackjack.Player{
+			Name:           name,
+			Cash:           100,
+			Bet:            blackjack.AiBet,
+			Decide:         blackjack.AiActionBasic,
+			AiRoundsToPlay: 20,
+		})
+	}
+
+	for g.PlayAgain() {
+		err = g.PlayRound()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rounds, err := blackjack.ReadHandHistory(history)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return rounds
+}
+
+func TestReplay(t *testing.T) {
+	t.Parallel()
+
+	rounds := recordSession(t)
+
+	r := blackjack.NewReplay(rounds)
+	err := r.Run(io.Discard, strings.NewReader(""), 0, 0, false, blackjack.WithRendering(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(r.Mismatches) != 0 {
+		t.Fatalf("want replay to match, got: %v", r.Mismatches)
+	}
+}
+
+func TestReplayToRound(t *testing.T) {
+	t.Parallel()
+
+	rounds := recordSession(t)
+
+	// an engine regression in a later round is not reached
+	rounds[10].Outcomes[0].Payout += 1
+
+	r := blackjack.NewReplay(rounds)
+	err := r.Run(io.Discard, strings.NewReader(""), 0, 5, false, blackjack.WithRendering(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(r.Mismatches) != 0 {
+		t.Fatalf("want replay to match, got: %v", r.Mismatches)
+	}
+
+	r = blackjack.NewReplay(rounds)
+	err = r.Run(io.Discard, strings.NewReader(""), 0, 0, false, blackjack.WithRendering(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := 1
+	got := len(r.Mismatches)
+
+	if want != got {
+		t.Fatalf("want: %d mismatch, got: %v", want, r.Mismatches)
+	}
+}
+
+func TestReplayIsDealtInstantly(t *testing.T) {
+	t.Parallel()
+
+	rounds := recordSession(t)
+
+	start := time.Now()
+	r := blackjack.NewReplay(rounds)
+	err := r.Run(io.Discard, strings.NewReader(""), 0, 1, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if time.Since(start) > 500*time.Millisecond {
+		t.Fatalf("want a rendered replay dealt without pauses, took %s", time.Since(start))
+	}
+}
@@ -0,0 +1,351 @@
+package blackjack
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+
+	"github.com/mbarley333/cards"
+)
+
+// Source is a seeded random source that counts how many numbers it has
+// handed out since Start was seeded, so its state can be saved and
+// restored
+type Source struct {
+	Start int64
+	Draws uint64
+	src   rand.Source64
+}
+
+func NewSource(seed int64) *Source {
+	return &Source{
+		Start: seed,
+		src:   rand.NewSource(seed).(rand.Source64),
+	}
+}
+
+// RestoreSource seeds a source and moves it on by draws numbers
+func RestoreSource(seed int64, draws uint64) *Source {
+	s := NewSource(seed)
+	for s.Draws < draws {
+		s.Int63()
+	}
+	return s
+}
+
+func (s *Source) Int63() int64 {
+	s.Draws++
+	return s.src.Int63()
+}
+
+func (s *Source) Uint64() uint64 {
+	s.Draws++
+	return s.src.Uint64()
+}
+
+func (s *Source) Seed(seed int64) {
+	s.Start = seed
+	s.Draws = 0
+	s.src.Seed(seed)
+}
+
+// SavedGame is everything needed to carry on a game between rounds
+type SavedGame struct {
+	Shoe                 []cards.Card   `json:"shoe"`
+	ShoeId               int            `json:"shoeId"`
+	DeckCount            int            `json:"deckCount"`
+	IsIncomingDeck       bool           `json:"isIncomingDeck"`
+	CardsDealt           int            `json:"cardsDealt"`
+	IncomingDeckPosition int            `json:"incomingDeckPosition"`
+	CardCounter          CardCounter    `json:"cardCounter"`
+	CountSystem          CountSystem    `json:"countSystem"`
+	Practice             *SavedPractice `json:"practice,omitempty"`
+	Seed                 int64          `json:"seed"`
+	RandomDraws          uint64         `json:"randomDraws"`
+	Round                int            `json:"round"`
+	Variant              Variant        `json:"variant"`
+	BlackjackTiePush     bool           `json:"blackjackTiePush"`
+	SeatCount            int            `json:"seatCount"`
+	NoMidShoeEntry       bool           `json:"noMidShoeEntry"`
+	Limits               BetLimits      `json:"limits"`
+	Players              []SavedPlayer  `json:"players"`
+	WaitingPlayers       []SavedPlayer  `json:"waitingPlayers"`
+}
+
+// SavedPractice is count practice between rounds, with the count of
+// the cards the players have seen and how far the count checks have
+// drawn from their random source
+type SavedPractice struct {
+	Cheat       bool                   `json:"cheat"`
+	CheckChance float64                `json:"checkChance"`
+	Scores      map[string]*CountScore `json:"scores"`
+	Count       CardCounter            `json:"count"`
+	Seen        int                    `json:"seen"`
+	Shuffled    *CardCounter           `json:"shuffled,omitempty"`
+	RandomDraws uint64                 `json:"randomDraws"`
+}
+
+// SavedPlayer is a player between rounds.  The strategy is saved by
+// player type name and looked up again on load
+type SavedPlayer struct {
+	Name           string   `json:"name"`
+	Type           string   `json:"type"`
+	Cash           int      `json:"cash"`
+	CurrentBet     int      `json:"currentBet"`
+	AiRoundsToPlay int      `json:"aiRoundsToPlay"`
+	RoundsPlayed   int      `json:"roundsPlayed"`
+	Record         Record   `json:"record"`
+	Spots          int      `json:"spots"`
+	SpotRecords    []Record `json:"spotRecords"`
+	Seat           int      `json:"seat"`
+}
+
+// Save writes the game state to output.  It is meant to be called
+// between rounds, as hands in play are not saved
+func (g *Game) Save(output io.Writer) error {
+
+	if g.source == nil {
+		return fmt.Errorf("unable to save a game with a custom random source")
+	}
+
+	saved := SavedGame{
+		Shoe:                 g.Shoe.Cards,
+		ShoeId:               g.ShoeId,
+		DeckCount:            g.DeckCount,
+		IsIncomingDeck:       g.IsIncomingDeck,
+		CardsDealt:           g.CardsDealt,
+		IncomingDeckPosition: g.IncomingDeckPosition,
+		CardCounter:          g.CardCounter,
+		CountSystem:          g.CountSystem,
+		Seed:                 g.source.Start,
+		RandomDraws:          g.source.Draws,
+		Round:                g.Round,
+		Variant:              g.Variant,
+		BlackjackTiePush:     g.BlackjackTiePush,
+		SeatCount:            g.SeatCount,
+		NoMidShoeEntry:       g.NoMidShoeEntry,
+		Limits:               g.Limits,
+		Players:              []SavedPlayer{},
+		WaitingPlayers:       []SavedPlayer{},
+	}
+
+	if g.Practice != nil {
+		saved.Practice = g.Practice.save()
+	}
+
+	// a bot's process cannot be saved, bots are started again on resume
+	for _, player := range g.Players {
+		if player.Action != ActionQuit && !g.IsBot(player) {
+			saved.Players = append(saved.Players, savePlayer(player))
+		}
+	}
+
+	for _, player := range g.WaitingPlayers {
+		if !g.IsBot(player) {
+			saved.WaitingPlayers = append(saved.WaitingPlayers, savePlayer(player))
+		}
+	}
+
+	encoder := json.NewEncoder(output)
+	encoder.SetIndent("", "  ")
+	err := encoder.Encode(saved)
+	if err != nil {
+		return fmt.Errorf("unable to save game, %s", err)
+	}
+
+	return nil
+}
+
+func (p *CountPractice) save() *SavedPractice {
+
+	saved := &SavedPractice{
+		Cheat:       p.Cheat,
+		CheckChance: p.CheckChance,
+		Scores:      p.Scores,
+		Count:       p.count,
+		Seen:        p.seen,
+		Shuffled:    p.shuffled,
+	}
+	if p.source != nil {
+		saved.RandomDraws = p.source.Draws
+	}
+
+	return saved
+}
+
+// restore picks count practice up where it was saved.  The count checks
+// draw from a source seeded by the game, as they did before the save
+func (p *CountPractice) restore(saved *SavedPractice, seed int64) {
+
+	p.Cheat = saved.Cheat
+	p.CheckChance = saved.CheckChance
+	p.Scores = saved.Scores
+	if p.Scores == nil {
+		p.Scores = map[string]*CountScore{}
+	}
+	p.count = saved.Count
+	p.seen = saved.Seen
+	p.hidden = nil
+	p.shuffled = saved.Shuffled
+	p.source = RestoreSource(seed, saved.RandomDraws)
+	p.random = rand.New(p.source)
+}
+
+func savePlayer(player *Player) SavedPlayer {
+	return SavedPlayer{
+		Name:           player.Name,
+		Type:           player.Type.String(),
+		Cash:           player.Cash,
+		CurrentBet:     player.CurrentBet,
+		AiRoundsToPlay: player.AiRoundsToPlay,
+		RoundsPlayed:   player.RoundsPlayed,
+		Record:         player.Record,
+		Spots:          player.Spots,
+		SpotRecords:    player.SpotRecords,
+		Seat:           player.Seat,
+	}
+}
+
+// SaveFile saves the game to path, replacing the file only once the
+// new save is complete
+func (g *Game) SaveFile(path string) error {
+
+	tmp := path + ".tmp"
+	file, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("unable to create save file, %s", err)
+	}
+
+	err = g.Save(file)
+	if err != nil {
+		file.Close()
+		os.Remove(tmp)
+		return err
+	}
+
+	err = file.Close()
+	if err != nil {
+		return fmt.Errorf("unable to write save file, %s", err)
+	}
+
+	return os.Rename(tmp, path)
+}
+
+// LoadGame restores a saved game.  Options such as output, input and
+// subscribers are applied as for a new game
+func LoadGame(input io.Reader, opts ...Option) (*Game, error) {
+
+	saved := SavedGame{}
+	err := json.NewDecoder(input).Decode(&saved)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read saved game, %s", err)
+	}
+
+	opts = append([]Option{
+		WithSeed(saved.Seed),
+		WithDeckCount(saved.DeckCount),
+		WithCountSystem(saved.CountSystem),
+		WithVariant(saved.Variant),
+		WithBlackjackTiePush(saved.BlackjackTiePush),
+		WithSeatCount(saved.SeatCount),
+		WithNoMidShoeEntry(saved.NoMidShoeEntry),
+		WithBetLimits(saved.Limits.Min, saved.Limits.Max),
+		WithNumberOfHumanPlayers(0),
+	}, opts...)
+
+	g, err := NewBlackjackGame(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// a game saved in count practice carries on in it
+	if saved.Practice != nil {
+		if g.Practice == nil {
+			WithCountPractice(saved.Practice.Cheat)(g)
+		}
+		g.Practice.restore(saved.Practice, saved.Seed)
+	}
+
+	for _, s := range saved.Players {
+		player, err := loadPlayer(s)
+		if err != nil {
+			return nil, err
+		}
+		if player.Seat > 0 {
+			err = g.SitPlayer(player, player.Seat)
+		} else {
+			err = g.AddPlayer(player)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to seat %s, %s", player.Name, err)
+		}
+		if player.Type == PlayerTypeHuman {
+			g.LoadProfile(player)
+		}
+	}
+
+	for _, s := range saved.WaitingPlayers {
+		player, err := loadPlayer(s)
+		if err != nil {
+			return nil, err
+		}
+		player.Variant = g.Variant
+		player.Theme = g.Theme
+		player.Limits = g.Limits
+		player.Waiting = true
+		g.WaitingPlayers = append(g.WaitingPlayers, player)
+		if player.Type == PlayerTypeHuman {
+			g.LoadProfile(player)
+		}
+	}
+
+	g.Shoe = cards.Deck{Cards: saved.Shoe, Count: saved.DeckCount}
+	g.ShoeId = saved.ShoeId
+	g.IsIncomingDeck = saved.IsIncomingDeck
+	g.CardsDealt = saved.CardsDealt
+	g.IncomingDeckPosition = saved.IncomingDeckPosition
+	g.CardCounter = saved.CardCounter
+	g.Round = saved.Round
+	g.source = RestoreSource(saved.Seed, saved.RandomDraws)
+	g.random = rand.New(g.source)
+
+	return g, nil
+}
+
+func loadPlayer(s SavedPlayer) (*Player, error) {
+
+	var playerType PlayerType
+	found := false
+	for t, name := range PlayerTypeNameMap {
+		if name == s.Type {
+			playerType = t
+			found = true
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("unknown player type %q for %s", s.Type, s.Name)
+	}
+
+	player := &Player{
+		Name:           s.Name,
+		Type:           playerType,
+		Decide:         PlayerTypeMap[playerType],
+		Bet:            PlayerTypeBetMap[playerType],
+		Switch:         PlayerTypeSwitchMap[playerType],
+		Cash:           s.Cash,
+		CurrentBet:     s.CurrentBet,
+		AiRoundsToPlay: s.AiRoundsToPlay,
+		RoundsPlayed:   s.RoundsPlayed,
+		Record:         s.Record,
+		Spots:          s.Spots,
+		SpotRecords:    s.SpotRecords,
+		Seat:           s.Seat,
+		Hands: []*Hand{
+			{Id: 1},
+		},
+	}
+
+	return player, nil
+}
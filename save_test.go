@@ -0,0 +1,187 @@
+package blackjack_test
+
+import (
+	"blackjack"
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSaveAndLoadGame(t *testing.T) {
+	t.Parallel()
+
+	g, err := blackjack.NewBlackjackGame(
+		blackjack.WithOutput(io.Discard),
+		blackjack.WithRendering(false),
+		blackjack.WithSeed(3),
+		blackjack.WithDeckCount(1),
+		blackjack.WithSeatCount(7),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g.AddPlayer(&blackjack.Player{
+		Name:           "Mirage",
+		Type:           blackjack.PlayerTypeAiBasic,
+		Cash:           100,
+		Bet:            blackjack.AiBet,
+		Decide:         blackjack.AiActionBasic,
+		AiRoundsToPlay: 40,
+	})
+
+	for i := 0; i < 5; i++ {
+		g.PlayRound()
+	}
+
+	saved := &bytes.Buffer{}
+	err = g.Save(saved)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := blackjack.LoadGame(saved,
+		blackjack.WithOutput(io.Discard),
+		blackjack.WithRendering(false),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !cmp.Equal(g.Shoe.Cards, loaded.Shoe.Cards) {
+		t.Fatal(cmp.Diff(g.Shoe.Cards, loaded.Shoe.Cards))
+	}
+
+	// enough rounds to go through new shoes dealt from the restored seed
+	for g.PlayAgain() {
+		g.PlayRound()
+		loaded.PlayRound()
+	}
+
+	want := g.Shoe.Cards
+	got := loaded.Shoe.Cards
+
+	if !cmp.Equal(want, got) {
+		t.Fatal(cmp.Diff(want, got))
+	}
+
+	if g.ShoeId < 3 {
+		t.Fatalf("want at least 3 shoes dealt, got %d", g.ShoeId)
+	}
+
+	if g.Round != loaded.Round {
+		t.Fatalf("want: round %d, got: round %d", g.Round, loaded.Round)
+	}
+}
+
+func TestLoadPlayerStrategy(t *testing.T) {
+	t.Parallel()
+
+	g, err := blackjack.NewBlackjackGame(blackjack.WithSeatCount(7))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g.AddPlayer(&blackjack.Player{
+		Name: "Sideswipe",
+		Type: blackjack.PlayerTypeAiStandOnly,
+		Cash: 42,
+	})
+
+	saved := &bytes.Buffer{}
+	err = g.Save(saved)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := blackjack.LoadGame(saved)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := loaded.Players[0]
+	if p.Decide == nil || p.Bet == nil || p.Switch == nil {
+		t.Fatal("want strategy funcs restored from the player type")
+	}
+
+	want := blackjack.PlayerTypeAiStandOnly
+	got := p.Type
+
+	if want != got {
+		t.Fatalf("want: %s, got: %s", want, got)
+	}
+
+	if p.Cash != 42 || p.Seat != 1 {
+		t.Fatalf("want $42 in seat 1, got $%d in seat %d", p.Cash, p.Seat)
+	}
+}
+
+func TestSaveCountSystemAndPractice(t *testing.T) {
+	t.Parallel()
+
+	g, err := blackjack.NewBlackjackGame(
+		blackjack.WithOutput(io.Discard),
+		blackjack.WithRendering(false),
+		blackjack.WithSeed(5),
+		blackjack.WithDeckCount(1),
+		blackjack.WithCountSystem(blackjack.CountSystemOmegaII),
+		blackjack.WithCountPractice(false),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g.AddPlayer(&blackjack.Player{
+		Name:           "Hound",
+		Type:           blackjack.PlayerTypeAiBasic,
+		Cash:           100,
+		Bet:            blackjack.AiBet,
+		Decide:         blackjack.AiActionBasic,
+		AiRoundsToPlay: 40,
+	})
+
+	for i := 0; i < 3; i++ {
+		g.PlayRound()
+		g.CountCheck()
+	}
+	g.Practice.Scores["Hound"] = &blackjack.CountScore{Checks: 3, RunningRight: 2, TrueRight: 1}
+
+	saved := &bytes.Buffer{}
+	err = g.Save(saved)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := saved.String()
+
+	loaded, err := blackjack.LoadGame(saved,
+		blackjack.WithOutput(io.Discard),
+		blackjack.WithRendering(false),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if loaded.CountSystem != blackjack.CountSystemOmegaII {
+		t.Fatalf("want the count system restored, got %s", loaded.CountSystem)
+	}
+	if loaded.Practice == nil {
+		t.Fatal("want count practice restored")
+	}
+	if !cmp.Equal(g.Practice.Scores, loaded.Practice.Scores) {
+		t.Fatal(cmp.Diff(g.Practice.Scores, loaded.Practice.Scores))
+	}
+
+	// the running count, cards seen and check draws all carry over
+	again := &bytes.Buffer{}
+	err = loaded.Save(again)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := again.String()
+
+	if want != got {
+		t.Fatal(cmp.Diff(want, got))
+	}
+}
@@ -0,0 +1,313 @@
+package blackjack
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/mbarley333/cards"
+)
+
+const (
+	screenHome      = "\x1b[H"
+	screenClear     = "\x1b[2J"
+	screenClearLine = "\x1b[K"
+	screenClearDown = "\x1b[J"
+	screenRule      = "------------------------------------------------------------"
+	screenMeter     = 30
+)
+
+// ScreenKeysMap is the keyboard shortcuts shown at each stage
+var ScreenKeysMap = map[Stage]string{
+	StageBetting:   "(B)et  (Q)uit  (C)ount",
+	StageDeciding:  "(H)it  (S)tand  (D)ouble  S(P)lit  (?)Hint  (C)ount",
+	StageSwitching: "S(W)itch  (K)eep",
+}
+
+// Screen is the full-screen terminal view of the game.  The table is
+// laid out in fixed places and redrawn in place after every event, with
+// the game's messages kept in a few lines under the table and the
+// question being asked at the bottom
+type Screen struct {
+	ShowCount bool
+	Messages  int
+	game      *Game
+	output    io.Writer
+	mu        sync.Mutex
+	log       []string
+	line      string
+}
+
+// WithFullScreen draws the game full screen instead of line by line.
+// The running count is shown on the screen when showCount is set
+func WithFullScreen(showCount bool) Option {
+	return func(g *Game) error {
+		g.FullScreen = true
+		g.ShowCount = showCount
+		return nil
+	}
+}
+
+// NewScreen draws the game on output.  The game's own output should
+// be the screen so that its messages are kept on the screen
+func NewScreen(g *Game, output io.Writer) *Screen {
+
+	s := &Screen{
+		ShowCount: g.ShowCount,
+		Messages:  8,
+		game:      g,
+		output:    output,
+	}
+	fmt.Fprint(output, screenClear)
+
+	return s
+}
+
+// IsTerminal is true when the file is a terminal rather than a pipe
+// or a file, so a screen drawn on it can be redrawn in place
+func IsTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// Write keeps the game's messages.  Writes are buffered until the end
+// of the line, and a line without an ending is the question being asked
+// and stays at the bottom of the screen
+func (s *Screen) Write(p []byte) (int, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lines := strings.Split(s.line+string(p), "\n")
+	for _, line := range lines[:len(lines)-1] {
+		s.keep(line)
+	}
+	s.line = lines[len(lines)-1]
+
+	s.draw()
+
+	return len(p), nil
+}
+
+// keep adds a line to the messages, dropping the oldest
+func (s *Screen) keep(line string) {
+
+	if strings.TrimSpace(line) == "" {
+		return
+	}
+	s.log = append(s.log, line)
+	if len(s.log) > s.Messages {
+		s.log = s.log[len(s.log)-s.Messages:]
+	}
+}
+
+// Input reads the answers to the screen's questions.  An answer ends
+// the question's line, so the question and its answer are kept as one
+// message
+func (s *Screen) Input(input io.Reader) io.Reader {
+	return &screenInput{screen: s, input: input}
+}
+
+type screenInput struct {
+	screen *Screen
+	input  io.Reader
+}
+
+func (r *screenInput) Read(p []byte) (int, error) {
+
+	n, err := r.input.Read(p)
+
+	s := r.screen
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lines := strings.Split(string(p[:n]), "\n")
+	for _, line := range lines[:len(lines)-1] {
+		s.keep(s.line + line)
+		s.line = ""
+	}
+
+	return n, err
+}
+
+// Update is the screen's subscriber, redrawing the table as it changes
+func (s *Screen) Update(e Event) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.draw()
+}
+
+func (s *Screen) draw() {
+
+	frame := strings.Builder{}
+	frame.WriteString(screenHome)
+
+	for _, line := range s.Frame() {
+		frame.WriteString(line + screenClearLine + "\n")
+	}
+	frame.WriteString(screenClearDown)
+	frame.WriteString(s.line)
+
+	fmt.Fprint(s.output, frame.String())
+}
+
+// Frame is the screen as lines of text, without the question being
+// asked
+func (s *Screen) Frame() []string {
+
+	g := s.game
+	lines := []string{}
+
+	header := fmt.Sprintf("BLACKJACK  %s  %d decks  Round %d", g.Variant, g.DeckCount, g.Round)
+	if g.Stage.Message() != "" {
+		header += "  " + g.Stage.Message()
+	}
+	lines = append(lines, header)
+	lines = append(lines, s.shoeLine())
+	lines = append(lines, screenRule)
+
+	lines = append(lines, s.dealerLine())
+	lines = append(lines, screenRule)
+
+	if g.SeatCount > 0 {
+		for seat := 1; seat <= g.SeatCount; seat++ {
+			player := g.SeatedPlayer(seat)
+			if player == nil {
+				lines = append(lines, fmt.Sprintf("  %2d  empty", seat))
+				continue
+			}
+			lines = append(lines, s.playerLines(strconv.Itoa(seat), player)...)
+		}
+	} else {
+		for _, player := range g.Players {
+			lines = append(lines, s.playerLines("-", player)...)
+		}
+	}
+	lines = append(lines, screenRule)
+
+	for i := 0; i < s.Messages; i++ {
+		if i < len(s.log) {
+			lines = append(lines, s.log[i])
+		} else {
+			lines = append(lines, "")
+		}
+	}
+	lines = append(lines, screenRule)
+	lines = append(lines, ScreenKeysMap[g.Stage])
+
+	return lines
+}
+
+// shoeLine is the shoe penetration meter and the count
+func (s *Screen) shoeLine() string {
+
+	g := s.game
+	penetration := g.Penetration()
+	filled := int(penetration * screenMeter)
+	if filled > screenMeter {
+		filled = screenMeter
+	}
+
+	line := fmt.Sprintf("Shoe [%s%s] %3.0f%% dealt", strings.Repeat("#", filled), strings.Repeat("-", screenMeter-filled), penetration*100)
+
+	if s.ShowCount {
+		if g.Practice != nil && !g.Practice.Cheat {
+			line += "  Count hidden"
+		} else {
+			line += "  Count " + strconv.Itoa(g.CardCounter.Count) + ", true " + strconv.FormatFloat(g.CardCounter.TrueCount, 'f', 1, 64)
+		}
+	}
+
+	return line
+}
+
+// dealerLine shows the dealer's cards, with the hole card face down
+// until the dealer plays
+func (s *Screen) dealerLine() string {
+
+	g := s.game
+	if g.Dealer == nil || len(g.Dealer.Hands) == 0 || len(g.Dealer.Hands[0].Cards) == 0 {
+		return "Dealer"
+	}
+
+	hand := g.Dealer.Hands[0]
+	exposed := g.Stage == StageDealerPlay || g.Stage == StageOutcome || g.Variant == VariantDoubleExposure
+	if exposed {
+		return fmt.Sprintf("Dealer %s %d", s.cardsString(hand.Cards), hand.Score())
+	}
+
+	return "Dealer [??]" + s.cardsString(hand.Cards[1:])
+}
+
+// playerLines shows a player's cash and a line for each of their hands
+func (s *Screen) playerLines(seat string, player *Player) []string {
+
+	g := s.game
+
+	// the player whose turn it is
+	marker := " "
+	if player == g.ActivePlayer && ScreenKeysMap[g.Stage] != "" {
+		marker = ">"
+	}
+	name := fmt.Sprintf("%s %2s  %-14s $%-6d", marker, seat, player.Name, player.Cash)
+
+	if player.Waiting {
+		return []string{name + " waiting for next shoe"}
+	}
+	if len(player.Hands) == 0 || player.Action == ActionQuit {
+		return []string{name}
+	}
+
+	lines := []string{}
+	for i, hand := range player.Hands {
+		line := name
+		if i > 0 {
+			line = strings.Repeat(" ", len(name))
+		}
+		if hand.Bet > 0 {
+			line += fmt.Sprintf(" bet $%-5d", hand.Bet)
+		} else {
+			line += strings.Repeat(" ", 11)
+		}
+		line += " " + s.handString(hand)
+		if g.Stage == StageOutcome && hand.Outcome != OutcomeNone {
+			line += "  " + hand.Outcome.String()
+		}
+		lines = append(lines, line)
+	}
+
+	return lines
+}
+
+// handString shows a hand and its score.  A double down dealt face
+// down stays hidden until the outcome
+func (s *Screen) handString(hand *Hand) string {
+
+	if len(hand.Cards) == 0 {
+		return ""
+	}
+	if hand.Action == ActionDoubleDown && s.game.Stage != StageOutcome && len(hand.Cards) > 2 {
+		return s.cardsString(hand.Cards[:2]) + "[??] ??"
+	}
+
+	return s.cardsString(hand.Cards) + " " + strconv.Itoa(hand.Score())
+}
+
+// cardsString draws the cards on one line in the game's theme
+func (s *Screen) cardsString(hand []cards.Card) string {
+
+	builder := strings.Builder{}
+	for _, card := range hand {
+		builder.WriteString(s.game.Theme.Card(card))
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,138 @@
+package blackjack_test
+
+import (
+	"blackjack"
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/mbarley333/cards"
+)
+
+// lastFrame is the screen as it was last drawn
+func lastFrame(output string) string {
+	frames := strings.Split(output, "\x1b[H")
+	return frames[len(frames)-1]
+}
+
+func TestScreenRedrawsTable(t *testing.T) {
+	t.Parallel()
+
+	stack := []cards.Card{
+		{Rank: cards.Ten, Suit: cards.Club},
+		{Rank: cards.Seven, Suit: cards.Club},
+		{Rank: cards.Nine, Suit: cards.Club},
+		{Rank: cards.King, Suit: cards.Spade},
+	}
+
+	output := &strings.Builder{}
+	g, err := blackjack.NewBlackjackGame(
+		blackjack.WithCustomDeck(cards.Deck{Cards: stack}),
+		blackjack.WithIncomingDeck(false),
+		blackjack.WithRendering(false),
+		blackjack.WithOutput(output),
+		blackjack.WithFullScreen(true),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := &blackjack.Player{
+		Name:           "Optimus",
+		Cash:           100,
+		Bet:            blackjack.AiBet,
+		Decide:         blackjack.AiActionStandOnly,
+		AiRoundsToPlay: 1,
+	}
+	g.AddPlayer(p)
+
+	g.ResetPlayers()
+	g.Betting()
+	g.OpeningDeal()
+
+	frame := lastFrame(output.String())
+	if !strings.Contains(frame, "Dealer [??]"+blackjack.ThemeDefault.Card(stack[3])) {
+		t.Fatalf("want the dealer's hole card face down, got: %s", frame)
+	}
+	if strings.Contains(frame, blackjack.ThemeDefault.Card(stack[1])) {
+		t.Fatalf("want the hole card hidden, got: %s", frame)
+	}
+	if !strings.Contains(frame, "Count -2, true") {
+		t.Fatalf("want the running count shown, got: %s", frame)
+	}
+	if !strings.Contains(frame, "Shoe [#") && !strings.Contains(frame, "Shoe [-") {
+		t.Fatalf("want the shoe penetration meter, got: %s", frame)
+	}
+
+	g.Deciding()
+	g.DealerPlay()
+	g.Outcome(io.Discard)
+
+	frame = lastFrame(output.String())
+	if !strings.Contains(frame, "Dealer "+blackjack.ThemeDefault.Card(stack[1])+blackjack.ThemeDefault.Card(stack[3])+" 17") {
+		t.Fatalf("want the dealer's hand turned over, got: %s", frame)
+	}
+	if !strings.Contains(frame, "Optimus") || !strings.Contains(frame, "19  Win") {
+		t.Fatalf("want the player's hand and outcome, got: %s", frame)
+	}
+}
+
+func TestScreenKeepsQuestionAtBottom(t *testing.T) {
+	t.Parallel()
+
+	g, err := blackjack.NewBlackjackGame(
+		blackjack.WithRendering(false),
+		blackjack.WithOutput(io.Discard),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output := &strings.Builder{}
+	s := blackjack.NewScreen(g, output)
+	s.Messages = 2
+
+	for i := 1; i <= 3; i++ {
+		fmt.Fprintf(s, "message %d\n", i)
+	}
+	fmt.Fprint(s, "Optimus enter (B)et or (Q)uit [b]:")
+
+	frame := lastFrame(output.String())
+	if strings.Contains(frame, "message 1") {
+		t.Fatalf("want only the last %d messages kept, got: %s", s.Messages, frame)
+	}
+	if !strings.Contains(frame, "message 2") || !strings.Contains(frame, "message 3") {
+		t.Fatalf("want the last messages shown, got: %s", frame)
+	}
+	if !strings.HasSuffix(frame, "Optimus enter (B)et or (Q)uit [b]:") {
+		t.Fatalf("want the question at the bottom, got: %s", frame)
+	}
+}
+
+func TestScreenKeepsAnsweredQuestion(t *testing.T) {
+	t.Parallel()
+
+	g, err := blackjack.NewBlackjackGame(
+		blackjack.WithRendering(false),
+		blackjack.WithOutput(io.Discard),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output := &strings.Builder{}
+	s := blackjack.NewScreen(g, output)
+	input := bufio.NewReader(s.Input(strings.NewReader("b\n")))
+
+	fmt.Fprint(s, "Optimus ")
+	fmt.Fprint(s, "enter (B)et or (Q)uit [b]:")
+	input.ReadString('\n')
+	fmt.Fprint(s, "Optimus bets $10\n")
+
+	frame := lastFrame(output.String())
+	if !strings.Contains(frame, "Optimus enter (B)et or (Q)uit [b]:b\x1b[K\nOptimus bets $10") {
+		t.Fatalf("want the answered question kept on its own line, got: %q", frame)
+	}
+}
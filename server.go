@@ -0,0 +1,845 @@
+package blackjack
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The server talks to clients one line at a time.  MSG lines are shown,
+// ASK lines are a question the client answers with a single line and a
+// BYE line ends the session.  A client that answers the name question
+// with WATCH, and optionally a delay in milliseconds, watches the table
+// without a seat
+const (
+	ServerMessage  = "MSG"
+	ServerQuestion = "ASK"
+	ServerGoodbye  = "BYE"
+	ServerWatch    = "WATCH"
+)
+
+// Server hosts a table over TCP.  Every client plays as a human player
+// reading from and writing to its own connection, while whatever is
+// said to the table goes out to all of them
+type Server struct {
+	Game          *Game
+	ReconnectWait time.Duration
+	WriteTimeout  time.Duration
+	listener      net.Listener
+	mu            sync.Mutex
+	clients       map[string]*Client
+	joining       []*Client
+	spectators    map[*Client]*Spectator
+	joined        chan struct{}
+	done          chan struct{}
+	closeOnce     sync.Once
+	status        TableStatus
+	ctx           context.Context
+	cancel        context.CancelFunc
+}
+
+// Client is the connection of a remote player.  A client that drops
+// stands on its hands and keeps its seat, so the player can connect
+// again under the same name with the token it was given on joining.
+// The table waits for it at the next bet and after that sits it out
+// until it is back
+type Client struct {
+	Player      *Player
+	server      *Server
+	mu          sync.Mutex
+	conn        net.Conn
+	token       string
+	partial     []byte
+	lines       chan string
+	gone        chan struct{}
+	reconnected chan struct{}
+	left        chan struct{}
+	absent      bool
+}
+
+// TableStatus is a summary of a table for a lobby.  The shoe is as it
+// was after the last round
+type TableStatus struct {
+	Variant     Variant
+	Decks       int
+	Limits      BetLimits
+	Seats       int
+	Seated      int
+	Round       int
+	Penetration float64
+}
+
+// DefaultWriteTimeout is how long a line to a client may take before
+// the client is dropped, so one that stops reading cannot hold up the
+// table
+const DefaultWriteTimeout = 10 * time.Second
+
+// NewServer creates the table served on listener.  Options are applied
+// as for a new game, with the game's output going to every client.  A
+// table without a listener only takes players handed over with Seat
+func NewServer(listener net.Listener, opts ...Option) (*Server, error) {
+
+	s := &Server{
+		ReconnectWait: 30 * time.Second,
+		WriteTimeout:  DefaultWriteTimeout,
+		listener:      listener,
+		clients:       map[string]*Client{},
+		spectators:    map[*Client]*Spectator{},
+		joined:        make(chan struct{}, 1),
+		done:          make(chan struct{}),
+	}
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+
+	opts = append([]Option{
+		WithOutput(tableWriter{server: s}),
+		WithInput(strings.NewReader("")),
+		WithNumberOfHumanPlayers(0),
+		WithTheme(ThemeMono),
+		WithSubscriber(s.showSpectators),
+	}, opts...)
+
+	g, err := NewBlackjackGame(opts...)
+	if err != nil {
+		return nil, err
+	}
+	s.Game = g
+	s.updateStatus()
+
+	return s, nil
+}
+
+// Run accepts clients and plays rounds until the server is closed.
+// Clients that connect during a round are seated before the next one
+func (s *Server) Run() error {
+
+	if s.listener != nil {
+		go s.accept()
+	}
+
+	for {
+		s.seatJoining()
+		nobody := s.sitOutAbsent()
+		s.updateStatus()
+
+		if !s.Game.PlayAgain() || nobody {
+			select {
+			case <-s.joined:
+				continue
+			case <-s.done:
+				return nil
+			}
+		}
+
+		err := s.Game.PlayRoundContext(s.ctx)
+		if err != nil && s.ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		s.removeQuit()
+		s.updateStatus()
+
+		select {
+		case <-s.done:
+			return nil
+		default:
+		}
+	}
+}
+
+// Close stops taking clients and drops every connection.  Players still
+// seated are quit at their next bet
+func (s *Server) Close() error {
+
+	s.closeOnce.Do(func() {
+		close(s.done)
+		s.cancel()
+	})
+
+	var err error
+	if s.listener != nil {
+		err = s.listener.Close()
+	}
+
+	s.mu.Lock()
+	for _, c := range s.clients {
+		c.mu.Lock()
+		if c.conn != nil {
+			c.conn.Close()
+		}
+		c.mu.Unlock()
+	}
+	for c := range s.spectators {
+		c.mu.Lock()
+		c.conn.Close()
+		c.mu.Unlock()
+	}
+	s.mu.Unlock()
+
+	return err
+}
+
+func (s *Server) Addr() net.Addr {
+	if s.listener == nil {
+		return nil
+	}
+	return s.listener.Addr()
+}
+
+// Seat hands a lobby's client to the table.  The client is seated
+// before the next round and the returned channel is closed once the
+// player has left the table again
+func (s *Server) Seat(c *Client) (<-chan struct{}, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, found := s.clients[c.Player.Name]; found {
+		return nil, fmt.Errorf("%s is already at the table", c.Player.Name)
+	}
+
+	player := c.Player
+	player.Action = None
+	player.Seat = 0
+	player.Waiting = false
+	player.HandIndex = 0
+	player.Hands = []*Hand{{Id: 1}}
+
+	c.mu.Lock()
+	c.server = s
+	c.left = make(chan struct{})
+	c.mu.Unlock()
+	s.clients[player.Name] = c
+	s.joining = append(s.joining, c)
+
+	select {
+	case s.joined <- struct{}{}:
+	default:
+	}
+
+	return c.left, nil
+}
+
+// Status is the table as a lobby shows it
+func (s *Server) Status() TableStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status := s.status
+	status.Seated += len(s.joining)
+	return status
+}
+
+// updateStatus takes a summary of the table between rounds, when the
+// table's goroutine is the only one using the game
+func (s *Server) updateStatus() {
+
+	g := s.Game
+	status := TableStatus{
+		Variant:     g.Variant,
+		Decks:       g.DeckCount,
+		Limits:      g.Limits,
+		Seats:       g.SeatCount,
+		Seated:      len(g.Players) + len(g.WaitingPlayers),
+		Round:       g.Round,
+		Penetration: g.Penetration(),
+	}
+
+	s.mu.Lock()
+	s.status = status
+	s.mu.Unlock()
+}
+
+func (s *Server) accept() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+// handle greets a new connection, ties it to a new or returning player
+// and then passes the client's lines on until the connection drops
+func (s *Server) handle(conn net.Conn) {
+
+	scanner := bufio.NewScanner(conn)
+	ask := func(question string) (string, bool) {
+		fmt.Fprintf(conn, "%s %s\n", ServerQuestion, question)
+		if !scanner.Scan() {
+			return "", false
+		}
+		return strings.TrimSpace(scanner.Text()), true
+	}
+
+	var c *Client
+	for c == nil {
+		name, ok := ask("Enter your name: ")
+		if !ok {
+			conn.Close()
+			return
+		}
+		if name == "" {
+			continue
+		}
+
+		fields := strings.Fields(name)
+		if fields[0] == ServerWatch {
+			delay := 0
+			if len(fields) > 1 {
+				delay, _ = strconv.Atoi(fields[1])
+			}
+			s.spectate(conn, scanner, time.Duration(delay)*time.Millisecond)
+			return
+		}
+
+		s.mu.Lock()
+		existing, found := s.clients[name]
+		connected := found && existing.connected()
+		s.mu.Unlock()
+
+		if connected {
+			fmt.Fprintf(conn, "%s %s is already at the table, choose another name\n", ServerMessage, name)
+			continue
+		}
+
+		if found {
+			token, ok := ask(fmt.Sprintf("Enter the reconnect token for %s: ", name))
+			if !ok {
+				conn.Close()
+				return
+			}
+
+			// the seat is only taken back by one connection with the token
+			s.mu.Lock()
+			if s.clients[name] == existing && !existing.connected() && existing.token == token {
+				c = existing
+				c.attach(conn)
+			}
+			s.mu.Unlock()
+
+			if c == nil {
+				fmt.Fprintf(conn, "%s That is not the token for %s, choose another name\n", ServerMessage, name)
+				continue
+			}
+			c.message("Welcome back " + name)
+			break
+		}
+
+		answer, ok := ask(fmt.Sprintf("%s enter number of spots to play (1-%d) [1]: ", name, MaxSpots))
+		if !ok {
+			conn.Close()
+			return
+		}
+		spots, err := strconv.Atoi(answer)
+		if err != nil || spots < 1 || spots > MaxSpots {
+			spots = 1
+		}
+
+		token, err := newToken()
+		if err != nil {
+			fmt.Fprintf(conn, "%s Unable to join the table, %s\n", ServerGoodbye, err)
+			conn.Close()
+			return
+		}
+
+		joining := &Client{
+			server:      s,
+			token:       token,
+			lines:       make(chan string, 16),
+			reconnected: make(chan struct{}, 1),
+		}
+		joining.Player = HumanPlayer(name, spots)
+		joining.Player.Output = joining
+		joining.Player.Input = joining
+
+		s.mu.Lock()
+		_, taken := s.clients[name]
+		if !taken {
+			s.clients[name] = joining
+			s.joining = append(s.joining, joining)
+			joining.attach(conn)
+		}
+		s.mu.Unlock()
+
+		if taken {
+			fmt.Fprintf(conn, "%s %s is already at the table, choose another name\n", ServerMessage, name)
+			continue
+		}
+		c = joining
+		c.message(fmt.Sprintf("Your reconnect token is %s, keep it to take your seat back if you drop", token))
+	}
+
+	for scanner.Scan() {
+		c.queue(scanner.Text())
+	}
+
+	c.detach(conn)
+}
+
+// spectate shows the table to a client watching without a seat until
+// it answers q or drops
+func (s *Server) spectate(conn net.Conn, scanner *bufio.Scanner, delay time.Duration) {
+
+	c := &Client{conn: conn}
+	spectator := NewSpectator(c, delay)
+	spectator.Theme = s.Game.Theme
+
+	s.mu.Lock()
+	s.spectators[c] = spectator
+	s.mu.Unlock()
+
+	status := s.Status()
+	fmt.Fprintf(c, "Watching %s with %d of %d seats taken, (Q)uit to stop watching\n", status.Variant, status.Seated, status.Seats)
+
+	for scanner.Scan() {
+		if strings.ToLower(strings.TrimSpace(scanner.Text())) == "q" {
+			break
+		}
+	}
+
+	s.mu.Lock()
+	delete(s.spectators, c)
+	s.mu.Unlock()
+
+	spectator.Close()
+	c.goodbye("Thanks for watching")
+}
+
+// showSpectators passes the table's events on to everyone watching
+func (s *Server) showSpectators(e Event) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, spectator := range s.spectators {
+		spectator.Watch(e)
+	}
+}
+
+// seatJoining sits the clients that have connected since the last round
+func (s *Server) seatJoining() {
+
+	s.mu.Lock()
+	joining := s.joining
+	s.joining = nil
+	s.mu.Unlock()
+
+	for _, c := range joining {
+		s.Game.LoadProfile(c.Player)
+		err := s.Game.ChooseSeat(c.Player)
+		if err != nil {
+			s.release(c, err.Error())
+		}
+	}
+}
+
+// sitOutAbsent sits out the players whose clients have dropped and
+// missed a bet.  It is true when everyone at the table is sitting out
+func (s *Server) sitOutAbsent() bool {
+
+	s.mu.Lock()
+	clients := []*Client{}
+	for _, c := range s.clients {
+		clients = append(clients, c)
+	}
+	s.mu.Unlock()
+
+	for _, c := range clients {
+		c.mu.Lock()
+		c.Player.SittingOut = c.absent
+		c.mu.Unlock()
+	}
+
+	for _, player := range s.Game.Players {
+		if !player.SittingOut {
+			return false
+		}
+	}
+	return len(s.Game.Players) > 0 && len(s.Game.WaitingPlayers) == 0
+}
+
+// removeQuit says goodbye to the clients whose players have left
+func (s *Server) removeQuit() {
+
+	s.mu.Lock()
+	joining := map[*Client]bool{}
+	for _, c := range s.joining {
+		joining[c] = true
+	}
+	clients := []*Client{}
+	for _, c := range s.clients {
+		// clients that joined during the round are seated before the next
+		if !joining[c] {
+			clients = append(clients, c)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, c := range clients {
+		if s.isSeated(c.Player) {
+			continue
+		}
+		s.release(c, "Thanks for playing "+c.Player.Name)
+	}
+}
+
+// release lets go of a client that is no longer seated.  A lobby's
+// client goes back to the lobby, any other is sent on its way
+func (s *Server) release(c *Client, text string) {
+
+	s.remove(c)
+
+	c.mu.Lock()
+	left := c.left
+	c.left = nil
+	if left != nil {
+		c.send(ServerMessage, text)
+	}
+	c.mu.Unlock()
+
+	if left != nil {
+		close(left)
+		return
+	}
+	c.goodbye(text)
+}
+
+func (s *Server) isSeated(player *Player) bool {
+	for _, p := range s.Game.Players {
+		if p == player {
+			return true
+		}
+	}
+	for _, p := range s.Game.WaitingPlayers {
+		if p == player {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) remove(c *Client) {
+	s.mu.Lock()
+	delete(s.clients, c.Player.Name)
+	s.mu.Unlock()
+}
+
+// tableWriter sends what is said to the table to every client.  Each
+// client's writes have a deadline, so one that stops reading is dropped
+// instead of holding up the table
+type tableWriter struct {
+	server *Server
+}
+
+func (t tableWriter) Write(p []byte) (int, error) {
+
+	t.server.mu.Lock()
+	defer t.server.mu.Unlock()
+
+	for _, c := range t.server.clients {
+		c.Write(p)
+	}
+
+	return len(p), nil
+}
+
+func (c *Client) connected() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.conn != nil
+}
+
+// attach ties the client to conn and wakes anything waiting for it.
+// A server's client is attached with the server's lock held, so only
+// one connection can take a seat
+func (c *Client) attach(conn net.Conn) {
+
+	c.mu.Lock()
+	c.conn = conn
+	c.gone = make(chan struct{})
+	c.absent = false
+	server := c.server
+	c.mu.Unlock()
+
+	select {
+	case c.reconnected <- struct{}{}:
+	default:
+	}
+
+	// a table with everyone sitting out waits for someone to come back
+	if server != nil {
+		select {
+		case server.joined <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (c *Client) detach(conn net.Conn) {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn != conn {
+		return
+	}
+	conn.Close()
+	c.conn = nil
+	close(c.gone)
+	c.gone = nil
+}
+
+// send writes one protocol line.  A client that cannot take the line
+// in time is disconnected rather than holding up the table.  It must be
+// called with the lock held
+func (c *Client) send(kind, text string) {
+
+	if c.conn == nil {
+		return
+	}
+
+	timeout := DefaultWriteTimeout
+	if c.server != nil && c.server.WriteTimeout > 0 {
+		timeout = c.server.WriteTimeout
+	}
+
+	c.conn.SetWriteDeadline(time.Now().Add(timeout))
+	_, err := fmt.Fprintf(c.conn, "%s %s\n", kind, text)
+	if err != nil {
+		c.conn.Close()
+	}
+}
+
+// queue holds a line for the next question.  A client that sends more
+// lines than can be held is told the line was dropped
+func (c *Client) queue(line string) {
+	select {
+	case c.lines <- line:
+	default:
+		c.message(fmt.Sprintf("%q was dropped, answer once you are asked", line))
+	}
+}
+
+// message sends the client a message line
+func (c *Client) message(text string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.send(ServerMessage, text)
+}
+
+func (c *Client) goodbye(text string) {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.send(ServerGoodbye, text)
+	if c.conn != nil {
+		c.conn.Close()
+	}
+}
+
+// Write sends each complete line as a message.  A partial line is held
+// back as it is usually the start of a question
+func (c *Client) Write(p []byte) (int, error) {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.partial = append(c.partial, p...)
+	for {
+		i := bytes.IndexByte(c.partial, '\n')
+		if i < 0 {
+			break
+		}
+		c.send(ServerMessage, string(c.partial[:i]))
+		c.partial = c.partial[i+1:]
+	}
+
+	return len(p), nil
+}
+
+// Read asks the question held back by Write and returns the client's
+// answer as one line.  When the client has dropped the answer is made
+// up for it, after waiting a while for it to come back before the first
+// bet it misses
+func (c *Client) Read(p []byte) (int, error) {
+	return c.ReadContext(context.Background(), p)
+}
+
+// ReadContext is Read giving up once ctx is done
+func (c *Client) ReadContext(ctx context.Context, p []byte) (int, error) {
+
+	c.mu.Lock()
+	question := string(c.partial)
+	c.partial = nil
+	c.mu.Unlock()
+
+	for len(c.lines) > 0 {
+		<-c.lines
+	}
+
+	for {
+		c.mu.Lock()
+		c.send(ServerQuestion, question)
+		gone := c.gone
+		c.mu.Unlock()
+
+		if gone == nil {
+			if c.server != nil && c.Player.Dialog == DialogBetOrQuit {
+				if c.waitForReconnect(ctx) {
+					continue
+				}
+				c.sitOut()
+			}
+			return copy(p, c.absentAnswer()+"\n"), nil
+		}
+
+		select {
+		case line := <-c.lines:
+			return copy(p, line+"\n"), nil
+		case <-gone:
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+	}
+}
+
+func (c *Client) waitForReconnect(ctx context.Context) bool {
+	select {
+	case <-c.reconnected:
+		return true
+	case <-time.After(c.server.ReconnectWait):
+		return false
+	case <-c.server.done:
+		return false
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// sitOut keeps the seat of a player who has not come back in time, so
+// the table no longer waits for them
+func (c *Client) sitOut() {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.absent = true
+	c.Player.SittingOut = true
+}
+
+// absentAnswer plays safe for a player who is not connected
+func (c *Client) absentAnswer() string {
+	switch c.Player.Dialog {
+	case DialogBetOrQuit:
+		if c.Player.SittingOut {
+			return "b"
+		}
+		return "q"
+	case DialogHitOrStand, DialogHitDoubleStand, DialogHitSplitStand, DialogHitSplitDoubleStand:
+		return "s"
+	}
+	return ""
+}
+
+// Watch watches the table served over conn without a seat, showing it
+// on output with each event held back by delay
+func Watch(conn io.ReadWriter, output io.Writer, delay time.Duration) error {
+
+	scanner := bufio.NewScanner(conn)
+
+	for scanner.Scan() {
+		kind, text := scanner.Text(), ""
+		if i := strings.IndexByte(kind, ' '); i >= 0 {
+			kind, text = kind[:i], kind[i+1:]
+		}
+
+		switch kind {
+		case ServerMessage:
+			fmt.Fprintln(output, text)
+		case ServerQuestion:
+			fmt.Fprintf(conn, "%s %d\n", ServerWatch, delay.Milliseconds())
+		case ServerGoodbye:
+			fmt.Fprintln(output, text)
+			return nil
+		}
+	}
+
+	return scanner.Err()
+}
+
+// Connect plays at a table served over conn, showing the table on
+// output and answering its questions from input.  The table is still
+// shown while a question waits for an answer, so a countdown on the
+// question is seen as it runs
+func Connect(conn io.ReadWriter, output io.Writer, input io.Reader) error {
+
+	lines := make(chan string)
+	var scanErr error
+	go func() {
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		scanErr = scanner.Err()
+		close(lines)
+	}()
+
+	answers := make(chan string)
+	go func() {
+		reader := BufferedReader(input)
+		for {
+			answer, err := reader.ReadString('\n')
+			if err != nil && answer == "" {
+				close(answers)
+				return
+			}
+			answers <- strings.TrimRight(answer, "\r\n")
+		}
+	}()
+
+	// answers are only taken while a question is waiting
+	var asked chan string
+
+	for {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				return scanErr
+			}
+
+			kind, text := line, ""
+			if i := strings.IndexByte(kind, ' '); i >= 0 {
+				kind, text = kind[:i], kind[i+1:]
+			}
+
+			switch kind {
+			case ServerMessage:
+				fmt.Fprintln(output, text)
+			case ServerQuestion:
+				fmt.Fprint(output, text)
+				asked = answers
+			case ServerGoodbye:
+				fmt.Fprintln(output, text)
+				return nil
+			}
+
+		case answer, ok := <-asked:
+			if !ok {
+				return nil
+			}
+			fmt.Fprintln(conn, answer)
+			asked = nil
+		}
+	}
+}
@@ -0,0 +1,348 @@
+package blackjack_test
+
+import (
+	"blackjack"
+	"bufio"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mbarley333/cards"
+)
+
+// client reads server lines up to the next question or goodbye
+type client struct {
+	conn    net.Conn
+	scanner *bufio.Scanner
+}
+
+func dial(t *testing.T, addr net.Addr) *client {
+	conn, err := net.Dial("tcp", addr.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.SetDeadline(time.Now().Add(10 * time.Second))
+	return &client{conn: conn, scanner: bufio.NewScanner(conn)}
+}
+
+func (c *client) next(t *testing.T) []string {
+	lines := []string{}
+	for c.scanner.Scan() {
+		line := c.scanner.Text()
+		lines = append(lines, line)
+		if strings.HasPrefix(line, blackjack.ServerQuestion) || strings.HasPrefix(line, blackjack.ServerGoodbye) {
+			return lines
+		}
+	}
+	t.Fatalf("connection ended after %q", lines)
+	return nil
+}
+
+func (c *client) answer(t *testing.T, question, answer string) []string {
+	lines := c.next(t)
+	last := lines[len(lines)-1]
+	if !strings.Contains(last, question) {
+		t.Fatalf("want question %q, got: %q", question, lines)
+	}
+	fmt.Fprintln(c.conn, answer)
+	return lines
+}
+
+// reconnectToken finds the token the server or lobby gave on joining
+func reconnectToken(t *testing.T, lines []string) string {
+	for _, line := range lines {
+		if i := strings.Index(line, "token is "); i >= 0 {
+			return strings.TrimSuffix(strings.Fields(line[i+len("token is "):])[0], ",")
+		}
+	}
+	t.Fatalf("want a reconnect token, got: %q", lines)
+	return ""
+}
+
+func newTestServer(t *testing.T) *blackjack.Server {
+
+	stack := []cards.Card{
+		{Rank: cards.Ten, Suit: cards.Club},
+		{Rank: cards.Seven, Suit: cards.Club},
+		{Rank: cards.Nine, Suit: cards.Club},
+		{Rank: cards.King, Suit: cards.Spade},
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := blackjack.NewServer(listener,
+		blackjack.WithCustomDeck(cards.Deck{Cards: stack}),
+		blackjack.WithIncomingDeck(false),
+		blackjack.WithRendering(false),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.ReconnectWait = 5 * time.Second
+
+	done := make(chan error)
+	go func() {
+		done <- s.Run()
+	}()
+	t.Cleanup(func() {
+		s.Close()
+		<-done
+	})
+
+	return s
+}
+
+func TestServerPlaysRemotePlayer(t *testing.T) {
+	t.Parallel()
+
+	s := newTestServer(t)
+	c := dial(t, s.Addr())
+
+	c.answer(t, "Enter your name", "Ironhide")
+	c.answer(t, "number of spots", "1")
+	c.answer(t, "(B)et or (Q)uit", "b")
+	c.answer(t, "place your bet", "10")
+	c.answer(t, "(S)tand", "s")
+	lines := c.answer(t, "(B)et or (Q)uit", "q")
+
+	if !strings.Contains(strings.Join(lines, "\n"), "MSG Ironhide won $10") {
+		t.Fatalf("want the outcome sent to the client, got: %q", lines)
+	}
+
+	lines = c.next(t)
+	if !strings.HasPrefix(lines[len(lines)-1], blackjack.ServerGoodbye) {
+		t.Fatalf("want goodbye, got: %q", lines)
+	}
+}
+
+func TestServerReconnect(t *testing.T) {
+	t.Parallel()
+
+	s := newTestServer(t)
+	c := dial(t, s.Addr())
+
+	c.answer(t, "Enter your name", "Jazz")
+	c.answer(t, "number of spots", "1")
+	token := reconnectToken(t, c.answer(t, "(B)et or (Q)uit", "b"))
+	c.answer(t, "place your bet", "10")
+	c.next(t)
+
+	// drop at the decision, the server stands and then waits at the bet
+	c.conn.Close()
+
+	c = dial(t, s.Addr())
+	c.answer(t, "Enter your name", "Jazz")
+	c.answer(t, "reconnect token", token)
+	lines := c.answer(t, "(B)et or (Q)uit", "q")
+
+	if !strings.Contains(strings.Join(lines, "\n"), "MSG Welcome back Jazz") {
+		t.Fatalf("want welcome back, got: %q", lines)
+	}
+
+	lines = c.next(t)
+	if !strings.HasPrefix(lines[len(lines)-1], blackjack.ServerGoodbye) {
+		t.Fatalf("want goodbye, got: %q", lines)
+	}
+}
+
+func TestConnect(t *testing.T) {
+	t.Parallel()
+
+	s := newTestServer(t)
+
+	conn, err := net.Dial("tcp", s.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	output := &strings.Builder{}
+	input := strings.NewReader("Bumblebee\n1\nb\n10\ns\nq\n")
+
+	err = blackjack.Connect(conn, output, input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(output.String(), "Bumblebee won $10") {
+		t.Fatalf("want the round played over the connection, got: %s", output.String())
+	}
+}
+
+func TestServerSpectator(t *testing.T) {
+	t.Parallel()
+
+	s := newTestServer(t)
+
+	watcher := dial(t, s.Addr())
+	watcher.answer(t, "Enter your name", blackjack.ServerWatch)
+	watcher.until(t, "Watching Classic")
+
+	c := dial(t, s.Addr())
+	c.answer(t, "Enter your name", "Ironhide")
+	c.answer(t, "number of spots", "1")
+	c.answer(t, "(B)et or (Q)uit", "b")
+	c.answer(t, "place your bet", "10")
+	c.answer(t, "(S)tand", "s")
+
+	lines := watcher.until(t, "Dealer turns over the")
+	for _, line := range lines[:len(lines)-1] {
+		if strings.Contains(line, "7♣") {
+			t.Fatalf("want the hole card hidden until the dealer plays, got: %q", lines)
+		}
+	}
+	if !strings.Contains(strings.Join(lines, "\n"), "Ironhide bets $10") {
+		t.Fatalf("want the bet shown to the spectator, got: %q", lines)
+	}
+
+	watcher.until(t, "Ironhide won $10")
+}
+
+// until reads server lines up to the first one containing text
+func (c *client) until(t *testing.T, text string) []string {
+	lines := []string{}
+	for c.scanner.Scan() {
+		line := c.scanner.Text()
+		lines = append(lines, line)
+		if strings.Contains(line, text) {
+			return lines
+		}
+	}
+	t.Fatalf("want %q, connection ended after %q", text, lines)
+	return nil
+}
+
+func TestServerSitsOutDroppedPlayer(t *testing.T) {
+	t.Parallel()
+
+	stack := []cards.Card{}
+	for i := 0; i < 5; i++ {
+		stack = append(stack,
+			cards.Card{Rank: cards.Ten, Suit: cards.Club},
+			cards.Card{Rank: cards.Seven, Suit: cards.Club},
+			cards.Card{Rank: cards.Nine, Suit: cards.Club},
+			cards.Card{Rank: cards.King, Suit: cards.Spade},
+		)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := blackjack.NewServer(listener,
+		blackjack.WithCustomDeck(cards.Deck{Cards: stack}),
+		blackjack.WithIncomingDeck(false),
+		blackjack.WithRendering(false),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.ReconnectWait = 500 * time.Millisecond
+
+	done := make(chan error)
+	go func() {
+		done <- s.Run()
+	}()
+	t.Cleanup(func() {
+		s.Close()
+		<-done
+	})
+
+	jazz := dial(t, s.Addr())
+	jazz.answer(t, "Enter your name", "Jazz")
+	jazz.answer(t, "number of spots", "1")
+	token := reconnectToken(t, jazz.answer(t, "(B)et or (Q)uit", "b"))
+	jazz.answer(t, "place your bet", "10")
+	jazz.next(t)
+	jazz.conn.Close()
+
+	// the table waits once for Jazz and then plays on without them
+	ironhide := dial(t, s.Addr())
+	ironhide.answer(t, "Enter your name", "Ironhide")
+	ironhide.answer(t, "number of spots", "1")
+	ironhide.answer(t, "(B)et or (Q)uit", "b")
+	ironhide.answer(t, "place your bet", "10")
+	ironhide.answer(t, "(S)tand", "s")
+
+	start := time.Now()
+	ironhide.next(t)
+	if time.Since(start) >= s.ReconnectWait {
+		t.Fatalf("want no wait for a player sitting out, waited %s", time.Since(start))
+	}
+
+	jazz = dial(t, s.Addr())
+	jazz.answer(t, "Enter your name", "Jazz")
+	jazz.answer(t, "reconnect token", token)
+	fmt.Fprintln(ironhide.conn, "q")
+
+	lines := jazz.answer(t, "(B)et or (Q)uit", "q")
+	if !strings.Contains(strings.Join(lines, "\n"), "MSG Welcome back Jazz") {
+		t.Fatalf("want Jazz's seat kept, got: %q", lines)
+	}
+}
+
+func TestServerReconnectNeedsToken(t *testing.T) {
+	t.Parallel()
+
+	s := newTestServer(t)
+	c := dial(t, s.Addr())
+
+	c.answer(t, "Enter your name", "Jazz")
+	c.answer(t, "number of spots", "1")
+	token := reconnectToken(t, c.answer(t, "(B)et or (Q)uit", "b"))
+	c.answer(t, "place your bet", "10")
+	c.next(t)
+	c.conn.Close()
+
+	thief := dial(t, s.Addr())
+	thief.answer(t, "Enter your name", "Jazz")
+	thief.answer(t, "reconnect token", "starscream")
+	lines := thief.next(t)
+	if !strings.Contains(strings.Join(lines, "\n"), "not the token for Jazz") {
+		t.Fatalf("want the seat kept from a wrong token, got: %q", lines)
+	}
+	fmt.Fprintln(thief.conn, "Jazz")
+	thief.answer(t, "reconnect token", "")
+
+	c = dial(t, s.Addr())
+	c.answer(t, "Enter your name", "Jazz")
+	c.answer(t, "reconnect token", token)
+	lines = c.answer(t, "(B)et or (Q)uit", "q")
+	if !strings.Contains(strings.Join(lines, "\n"), "MSG Welcome back Jazz") {
+		t.Fatalf("want welcome back with the token, got: %q", lines)
+	}
+
+	lines = thief.next(t)
+	if strings.Contains(strings.Join(lines, "\n"), "Welcome back") {
+		t.Fatalf("want no seat for an empty token, got: %q", lines)
+	}
+}
+
+func TestServerTellsClientOfDroppedLine(t *testing.T) {
+	t.Parallel()
+
+	s := newTestServer(t)
+
+	ironhide := dial(t, s.Addr())
+	ironhide.answer(t, "Enter your name", "Ironhide")
+	ironhide.answer(t, "number of spots", "1")
+	ironhide.next(t)
+
+	// nobody asks Jazz anything until Ironhide has played the round
+	jazz := dial(t, s.Addr())
+	jazz.answer(t, "Enter your name", "Jazz")
+	jazz.answer(t, "number of spots", "1")
+	for i := 0; i < 20; i++ {
+		fmt.Fprintf(jazz.conn, "line %d\n", i)
+	}
+
+	lines := jazz.until(t, "was dropped")
+	if !strings.Contains(lines[len(lines)-1], `"line 16" was dropped`) {
+		t.Fatalf("want the first line past the buffer dropped, got: %q", lines)
+	}
+}
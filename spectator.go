@@ -0,0 +1,200 @@
+package blackjack
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/mbarley333/cards"
+)
+
+// SpectatorActionMap is what a spectator is told each action does
+var SpectatorActionMap = map[Action]string{
+	ActionHit:        "hits",
+	ActionStand:      "stands",
+	ActionQuit:       "quits",
+	ActionDoubleDown: "doubles down",
+	ActionSplit:      "splits",
+	ActionSwitch:     "switches cards",
+	ActionKeep:       "keeps cards",
+}
+
+// Spectator watches a table without a seat.  It is subscribed to the
+// game's events and shows every exposed card, bet, action and outcome
+// on its output.  The dealer's hole card and cards dealt face down stay
+// hidden until they are turned over.  Each event is held back by Delay
+// and the next one shown Delay after it, so fast AI players can be
+// watched in slow motion.  Cards are drawn in Theme
+type Spectator struct {
+	Output io.Writer
+	Delay  time.Duration
+	Theme  Theme
+	mu     sync.Mutex
+	queue  []string
+	closed bool
+	wake   chan struct{}
+	done   chan struct{}
+	hidden map[string]cards.Card
+	hands  map[string]map[int]bool
+}
+
+// NewSpectator starts showing events on output as they are watched
+func NewSpectator(output io.Writer, delay time.Duration) *Spectator {
+
+	s := &Spectator{
+		Output: output,
+		Delay:  delay,
+		wake:   make(chan struct{}, 1),
+		done:   make(chan struct{}),
+		hidden: map[string]cards.Card{},
+		hands:  map[string]map[int]bool{},
+	}
+
+	go s.show()
+
+	return s
+}
+
+// Watch is the spectator's subscriber.  It never holds up the table,
+// events are queued and shown in the background
+func (s *Spectator) Watch(e Event) {
+
+	text := s.describe(e)
+	if text == "" {
+		return
+	}
+
+	s.mu.Lock()
+	if !s.closed {
+		s.queue = append(s.queue, text)
+	}
+	s.mu.Unlock()
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Close shows whatever is still held back, without the delay, and
+// stops the spectator
+func (s *Spectator) Close() {
+
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+
+	<-s.done
+}
+
+func (s *Spectator) show() {
+
+	defer close(s.done)
+
+	for {
+		s.mu.Lock()
+		if len(s.queue) == 0 {
+			closed := s.closed
+			s.mu.Unlock()
+			if closed {
+				return
+			}
+			<-s.wake
+			continue
+		}
+		text := s.queue[0]
+		s.queue = s.queue[1:]
+		closed := s.closed
+		s.mu.Unlock()
+
+		if s.Delay > 0 && !closed {
+			time.Sleep(s.Delay)
+		}
+		fmt.Fprintln(s.Output, text)
+	}
+}
+
+// describe is the event as the spectator sees it, or nothing for
+// events a spectator is not shown
+func (s *Spectator) describe(e Event) string {
+
+	switch e.Type {
+	case EventRoundStarted:
+		s.hands = map[string]map[int]bool{}
+		return "***** Round " + strconv.Itoa(e.Round) + " *****"
+
+	case EventShoeShuffled:
+		return "New shoe shuffled"
+
+	case EventPlayerSeated:
+		if e.Seat > 0 {
+			return fmt.Sprintf("%s sits down in seat %d with $%d", e.Player, e.Seat, e.Cash)
+		}
+		return fmt.Sprintf("%s joins the table with $%d", e.Player, e.Cash)
+
+	case EventPlayerLeft:
+		return fmt.Sprintf("%s leaves the table with $%d", e.Player, e.Cash)
+
+	case EventBetPlaced:
+		return fmt.Sprintf("%s bets $%d", s.label(e), e.Bet)
+
+	case EventCardDealt:
+		if e.FaceDown {
+			s.hidden[handKey(e)] = e.Card
+			return s.label(e) + " is dealt [??]"
+		}
+		return s.label(e) + " is dealt the " + s.Theme.Card(e.Card)
+
+	case EventHoleCardRevealed:
+		delete(s.hidden, handKey(e))
+		return e.Player + " turns over the " + s.Theme.Card(e.Card)
+
+	case EventActionTaken:
+		verb, ok := SpectatorActionMap[e.Action]
+		if !ok {
+			return ""
+		}
+		return s.label(e) + " " + verb
+
+	case EventHandSettled:
+		text := ""
+		card, ok := s.hidden[handKey(e)]
+		if ok {
+			delete(s.hidden, handKey(e))
+			text = s.label(e) + " turns over the " + s.Theme.Card(card) + "\n"
+		}
+
+		payout := ""
+		if e.Outcome != OutcomeTie {
+			payout = strconv.Itoa(absInt(e.Payout))
+		}
+		return fmt.Sprintf("%s%s%s%s (%s).  Cash available: $%d", text, s.label(e), BalanceReportMap[e.Outcome], payout, e.Outcome, e.Cash)
+	}
+
+	return ""
+}
+
+// label names the player, and the hand once the player has more than
+// one hand this round
+func (s *Spectator) label(e Event) string {
+
+	key := e.Player + "/" + strconv.Itoa(e.Seat)
+	if s.hands[key] == nil {
+		s.hands[key] = map[int]bool{}
+	}
+	if e.HandId > 0 {
+		s.hands[key][e.HandId] = true
+	}
+
+	if len(s.hands[key]) > 1 {
+		return e.Player + " hand #" + strconv.Itoa(e.HandId)
+	}
+	return e.Player
+}
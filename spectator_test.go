@@ -0,0 +1,119 @@
+package blackjack_test
+
+import (
+	"blackjack"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mbarley333/cards"
+)
+
+// lockedBuilder is written by the spectator's goroutine and read by the test
+type lockedBuilder struct {
+	mu sync.Mutex
+	b  strings.Builder
+}
+
+func (l *lockedBuilder) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.b.Write(p)
+}
+
+func (l *lockedBuilder) String() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.b.String()
+}
+
+func TestSpectatorHidesFaceDownCards(t *testing.T) {
+	t.Parallel()
+
+	stack := []cards.Card{
+		{Rank: cards.Ten, Suit: cards.Club},
+		{Rank: cards.Seven, Suit: cards.Club},
+		{Rank: cards.Six, Suit: cards.Club},
+		{Rank: cards.King, Suit: cards.Spade},
+		{Rank: cards.Five, Suit: cards.Heart},
+	}
+
+	output := &lockedBuilder{}
+	spectator := blackjack.NewSpectator(output, 0)
+	spectator.Theme = blackjack.ThemeASCII
+
+	g, err := blackjack.NewBlackjackGame(
+		blackjack.WithCustomDeck(cards.Deck{Cards: stack}),
+		blackjack.WithIncomingDeck(false),
+		blackjack.WithRendering(false),
+		blackjack.WithSubscriber(spectator.Watch),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := &blackjack.Player{Name: "Ironhide", Cash: 100}
+	g.AddPlayer(p)
+
+	g.StartRound()
+	_, err = g.PlaceBet(p, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = g.ApplyAction(p, blackjack.ActionDoubleDown)
+	if err != nil {
+		t.Fatal(err)
+	}
+	spectator.Close()
+
+	got := output.String()
+	for _, want := range []string{
+		"Ironhide joins the table with $100",
+		"Ironhide bets $10",
+		"Ironhide is dealt the [10C]",
+		"Dealer is dealt [??]",
+		"Ironhide doubles down",
+		"Ironhide is dealt [??]",
+		"Dealer turns over the [7C]",
+		"Ironhide turns over the [5H]",
+		"Ironhide won $20 (Win).  Cash available: $120",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("want %q shown to the spectator, got:\n%s", want, got)
+		}
+	}
+
+	reveal := strings.Index(got, "Dealer turns over the")
+	if strings.Contains(got[:reveal], "7♣") {
+		t.Fatalf("want the hole card hidden until the dealer plays, got:\n%s", got)
+	}
+
+	reveal = strings.Index(got, "Ironhide turns over the")
+	if strings.Contains(got[:reveal], "5♥") {
+		t.Fatalf("want the double down card hidden until the hand is settled, got:\n%s", got)
+	}
+}
+
+func TestSpectatorDelay(t *testing.T) {
+	t.Parallel()
+
+	output := &lockedBuilder{}
+	spectator := blackjack.NewSpectator(output, time.Second)
+
+	spectator.Watch(blackjack.Event{Type: blackjack.EventBetPlaced, Player: "Jazz", Bet: 5})
+	spectator.Watch(blackjack.Event{Type: blackjack.EventActionTaken, Player: "Jazz", Action: blackjack.ActionHit})
+
+	time.Sleep(50 * time.Millisecond)
+	if output.String() != "" {
+		t.Fatalf("want events held back by the delay, got: %q", output.String())
+	}
+
+	spectator.Close()
+
+	want := "Jazz bets $5\nJazz hits\n"
+	got := output.String()
+	if want != got {
+		t.Fatalf("want %q shown once the spectator closes, got %q", want, got)
+	}
+}
@@ -0,0 +1,188 @@
+package blackjack
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/mbarley333/cards"
+)
+
+// Theme is how cards are drawn on the terminal
+type Theme int
+
+const (
+	ThemeDefault Theme = iota
+	ThemeColor
+	ThemeMono
+	ThemeASCII
+	ThemeArt
+	ThemeArtMono
+)
+
+var ThemeMap = map[Theme]string{
+	ThemeDefault: "Default",
+	ThemeColor:   "Color",
+	ThemeMono:    "Mono",
+	ThemeASCII:   "ASCII",
+	ThemeArt:     "Art",
+	ThemeArtMono: "ArtMono",
+}
+
+var ThemeInputMap = map[string]Theme{
+	"default": ThemeDefault,
+	"color":   ThemeColor,
+	"mono":    ThemeMono,
+	"ascii":   ThemeASCII,
+	"art":     ThemeArt,
+}
+
+func (t Theme) String() string {
+	return ThemeMap[t]
+}
+
+// SuitColorMap is the colour each suit is drawn in, a four colour deck
+// so that suits can be told apart at a glance
+var SuitColorMap = map[cards.Suit]string{
+	cards.Spade:   "\033[0m",
+	cards.Heart:   "\033[31m",
+	cards.Diamond: "\033[34m",
+	cards.Club:    "\033[32m",
+}
+
+// SuitASCIIMap is the letter each suit is drawn with when the terminal
+// cannot show the Unicode suits
+var SuitASCIIMap = map[cards.Suit]string{
+	cards.Spade:   "S",
+	cards.Heart:   "H",
+	cards.Diamond: "D",
+	cards.Club:    "C",
+}
+
+const colorReset = "\033[0m"
+
+// WithTheme sets how cards are drawn for the players and the dealer
+func WithTheme(theme Theme) Option {
+	return func(g *Game) error {
+		_, ok := ThemeMap[theme]
+		if !ok {
+			return fmt.Errorf("unknown theme %d", theme)
+		}
+		g.Theme = theme
+		return nil
+	}
+}
+
+// Colored is true when the theme draws in colour on output.  The
+// default and art themes colour only a terminal, and not when NO_COLOR
+// is set, see no-color.org
+func (t Theme) Colored(output io.Writer) bool {
+	switch t {
+	case ThemeDefault, ThemeArt:
+		file, ok := output.(*os.File)
+		return ok && IsTerminal(file) && os.Getenv("NO_COLOR") == ""
+	case ThemeColor:
+		return true
+	}
+	return false
+}
+
+// ThemeFor is the theme cards are drawn in on output.  The default
+// theme is colour on a terminal and mono on a pipe, a file or a
+// connection, and the art theme loses its colour the same way
+func ThemeFor(theme Theme, output io.Writer) Theme {
+
+	switch theme {
+	case ThemeDefault:
+		if theme.Colored(output) {
+			return ThemeColor
+		}
+		return ThemeMono
+	case ThemeArt:
+		if !theme.Colored(output) {
+			return ThemeArtMono
+		}
+	}
+	return theme
+}
+
+// colored is true for the themes that draw in colour once ThemeFor has
+// picked the theme for the output
+func (t Theme) colored() bool {
+	return t == ThemeColor || t == ThemeArt
+}
+
+// Card draws a single card on one line
+func (t Theme) Card(card cards.Card) string {
+
+	if card.Rank == 0 {
+		return "[??]"
+	}
+
+	rank := strings.TrimSuffix(card.Notation(), string(card.Suit))
+	if t == ThemeASCII {
+		return "[" + rank + SuitASCIIMap[card.Suit] + "]"
+	}
+	if !t.colored() {
+		return "[" + card.Notation() + "]"
+	}
+
+	return "[" + SuitColorMap[card.Suit] + card.Notation() + colorReset + "]"
+}
+
+// Hand draws the cards of a hand.  A card without a rank is face down.
+// The art theme draws the cards side by side over several lines, on
+// the lines after the one the hand starts on
+func (t Theme) Hand(hand []cards.Card) string {
+
+	if t != ThemeArt && t != ThemeArtMono {
+		builder := strings.Builder{}
+		for _, card := range hand {
+			builder.WriteString(t.Card(card))
+		}
+		return builder.String()
+	}
+
+	rows := make([]string, 5)
+	for i, card := range hand {
+		art := t.art(card)
+		for row := range rows {
+			if i > 0 {
+				rows[row] += " "
+			}
+			rows[row] += art[row]
+		}
+	}
+
+	return "\n" + strings.Join(rows, "\n")
+}
+
+// art is the card drawn five lines high
+func (t Theme) art(card cards.Card) []string {
+
+	if card.Rank == 0 {
+		return []string{
+			".-----.",
+			"|/////|",
+			"|/////|",
+			"|/////|",
+			"'-----'",
+		}
+	}
+
+	rank := strings.TrimSuffix(card.Notation(), string(card.Suit))
+	suit := string(card.Suit)
+	color, reset := "", ""
+	if t.colored() {
+		color, reset = SuitColorMap[card.Suit], colorReset
+	}
+
+	return []string{
+		".-----.",
+		"|" + color + fmt.Sprintf("%-2s", rank) + reset + "   |",
+		"|  " + color + suit + reset + "  |",
+		"|   " + color + fmt.Sprintf("%2s", rank) + reset + "|",
+		"'-----'",
+	}
+}
@@ -0,0 +1,142 @@
+package blackjack_test
+
+import (
+	"blackjack"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/mbarley333/cards"
+)
+
+func TestThemeCard(t *testing.T) {
+	t.Parallel()
+
+	card := cards.Card{Rank: cards.Ten, Suit: cards.Heart}
+
+	type testCase struct {
+		theme blackjack.Theme
+		want  string
+	}
+
+	tcs := []testCase{
+		{theme: blackjack.ThemeColor, want: "[\033[31m10♥\033[0m]"},
+		{theme: blackjack.ThemeMono, want: "[10♥]"},
+		{theme: blackjack.ThemeASCII, want: "[10H]"},
+	}
+
+	for _, tc := range tcs {
+		got := tc.theme.Card(card)
+		if tc.want != got {
+			t.Fatalf("%s: want %q, got %q", tc.theme, tc.want, got)
+		}
+	}
+}
+
+func TestThemeDefaultIsPlainOffTerminal(t *testing.T) {
+	t.Parallel()
+
+	card := cards.Card{Rank: cards.Ace, Suit: cards.Diamond}
+
+	// test output is not a terminal
+	want := "[A♦]"
+	got := blackjack.ThemeDefault.Card(card)
+	if want != got {
+		t.Fatalf("want no colour off a terminal, want %q, got %q", want, got)
+	}
+
+	if blackjack.ThemeFor(blackjack.ThemeDefault, &strings.Builder{}) != blackjack.ThemeMono {
+		t.Fatal("want the default theme mono when output is not a terminal")
+	}
+
+	if blackjack.ThemeFor(blackjack.ThemeColor, &strings.Builder{}) != blackjack.ThemeColor {
+		t.Fatal("want a chosen theme kept whatever the output")
+	}
+
+	g, err := blackjack.NewBlackjackGame(blackjack.WithOutput(&strings.Builder{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Theme != blackjack.ThemeMono {
+		t.Fatalf("want a game writing to a buffer drawn in mono, got %s", g.Theme)
+	}
+}
+
+func TestThemeArtPlainOffTerminal(t *testing.T) {
+	t.Parallel()
+
+	output := &strings.Builder{}
+	if blackjack.ThemeArt.Colored(output) {
+		t.Fatal("want no colour on output that is not a terminal")
+	}
+	if blackjack.ThemeFor(blackjack.ThemeArt, output) != blackjack.ThemeArtMono {
+		t.Fatal("want the art theme without colour when output is not a terminal")
+	}
+
+	g, err := blackjack.NewBlackjackGame(
+		blackjack.WithOutput(output),
+		blackjack.WithTheme(blackjack.ThemeArt),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	card := cards.Card{Rank: cards.Queen, Suit: cards.Heart}
+	got := g.Theme.Hand([]cards.Card{card})
+	if strings.Contains(got, "\033") || !strings.Contains(got, "♥") {
+		t.Fatalf("want the art drawn without colour, got %q", got)
+	}
+}
+
+func TestThemeDefaultHonoursNoColor(t *testing.T) {
+
+	t.Setenv("NO_COLOR", "1")
+	if blackjack.ThemeFor(blackjack.ThemeDefault, os.Stdout) != blackjack.ThemeMono {
+		t.Fatal("want mono with NO_COLOR set")
+	}
+}
+
+func TestThemeArtHand(t *testing.T) {
+	t.Parallel()
+
+	h := blackjack.Hand{
+		Cards: []cards.Card{
+			{Rank: cards.Ace, Suit: cards.Spade},
+			{Rank: cards.Ten, Suit: cards.Club},
+		},
+	}
+
+	got := h.DealerHandString(false, blackjack.ThemeArt)
+	lines := strings.Split(strings.TrimSuffix(got, "\n"), "\n")
+
+	want := 6
+	if len(lines) != want {
+		t.Fatalf("want the hand on %d lines, got %d: %q", want, len(lines), got)
+	}
+	if lines[0] != "Dealer has ??: " {
+		t.Fatalf("want the hand named on the first line, got %q", lines[0])
+	}
+	if !strings.Contains(lines[2], "|/////|") {
+		t.Fatalf("want the hole card face down, got %q", lines[2])
+	}
+	if strings.Contains(got, "♠") {
+		t.Fatalf("want the hole card hidden, got %q", got)
+	}
+}
+
+func TestThemeASCIIHand(t *testing.T) {
+	t.Parallel()
+
+	h := blackjack.Hand{
+		Cards: []cards.Card{
+			{Rank: cards.Ace, Suit: cards.Spade},
+			{Rank: cards.King, Suit: cards.Diamond},
+		},
+	}
+
+	want := "Optimus has 21: [AS][KD]\n"
+	got := h.HandString("Optimus", blackjack.ThemeASCII)
+	if want != got {
+		t.Fatalf("want %q, got %q", want, got)
+	}
+}
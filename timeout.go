@@ -0,0 +1,284 @@
+package blackjack
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// TimeoutPlay is what a human player plays when they run out of time
+type TimeoutPlay int
+
+const (
+	TimeoutStand TimeoutPlay = iota
+	TimeoutBasic
+)
+
+var TimeoutPlayMap = map[TimeoutPlay]string{
+	TimeoutStand: "Stand",
+	TimeoutBasic: "Basic",
+}
+
+func (t TimeoutPlay) String() string {
+	return TimeoutPlayMap[t]
+}
+
+var TimeoutPlayInputMap = map[string]TimeoutPlay{
+	"stand": TimeoutStand,
+	"basic": TimeoutBasic,
+}
+
+// countdownMarks are the seconds left at which the countdown is shown
+var countdownMarks = map[int]bool{30: true, 20: true, 10: true, 5: true, 3: true, 2: true, 1: true}
+
+// WithDecisionTimeout gives human players a time limit on every
+// decision.  The default of 0 waits for as long as the player takes
+func WithDecisionTimeout(timeout time.Duration) Option {
+	return func(g *Game) error {
+		if timeout < 0 {
+			return fmt.Errorf("decision timeout cannot be negative")
+		}
+		g.DecisionTimeout = timeout
+		return nil
+	}
+}
+
+// WithTimeBank gives every human player extra time to draw on once a
+// decision's own time has run out.  Time taken from the bank is gone
+// for as long as the player stays at the table
+func WithTimeBank(bank time.Duration) Option {
+	return func(g *Game) error {
+		if bank < 0 {
+			return fmt.Errorf("time bank cannot be negative")
+		}
+		g.TimeBank = bank
+		return nil
+	}
+}
+
+// WithTimeoutPlay is what is played for a player who runs out of time,
+// standing or the basic strategy play
+func WithTimeoutPlay(play TimeoutPlay) Option {
+	return func(g *Game) error {
+		g.TimeoutPlay = play
+		return nil
+	}
+}
+
+// contextReader is an input whose reads can be given up on
+type contextReader interface {
+	io.Reader
+	ReadContext(ctx context.Context, p []byte) (int, error)
+}
+
+// lineFeed reads an input a line at a time in the background, so a
+// read that times out does not lose the line that comes in after it
+type lineFeed struct {
+	lines chan string
+}
+
+func newLineFeed(input io.Reader) *lineFeed {
+
+	f := &lineFeed{lines: make(chan string)}
+
+	go func() {
+		reader := BufferedReader(input)
+		for {
+			line, err := reader.ReadString('\n')
+			if line != "" {
+				f.lines <- line
+			}
+			if err != nil {
+				close(f.lines)
+				return
+			}
+		}
+	}()
+
+	return f
+}
+
+func (f *lineFeed) Read(p []byte) (int, error) {
+	return f.ReadContext(context.Background(), p)
+}
+
+// ReadContext returns the next line, or gives up once ctx is done
+func (f *lineFeed) ReadContext(ctx context.Context, p []byte) (int, error) {
+	select {
+	case line, ok := <-f.lines:
+		if !ok {
+			return 0, io.EOF
+		}
+		return copy(p, line), nil
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+// contextInput is the input read so that it can be given up on.  Once
+// an input is fed in the background every read of it goes through the
+// same feed
+func (g *Game) contextInput(input io.Reader) contextReader {
+
+	if r, ok := input.(contextReader); ok {
+		return r
+	}
+
+	if g.feeds == nil {
+		g.feeds = map[io.Reader]*lineFeed{}
+	}
+	f, ok := g.feeds[input]
+	if !ok {
+		f = newLineFeed(input)
+		g.feeds[input] = f
+	}
+	return f
+}
+
+// decisionClock times a human player's decision.  It is read in place
+// of the player's input, counting down on the player's output, and
+// answers with the timeout play once the decision's time and the
+// player's time bank have both run out
+type decisionClock struct {
+	game     *Game
+	decision Decision
+	input    contextReader
+	output   io.Writer
+	ctx      context.Context
+	cancel   context.CancelFunc
+	start    time.Time
+	deadline time.Time
+	shown    int
+	banking  bool
+	expired  bool
+}
+
+// startClock starts timing the decision.  There is no clock for AI
+// players or when decisions are not timed
+func (g *Game) startClock(ctx context.Context, decision Decision) *decisionClock {
+
+	player := decision.Player
+	if g.DecisionTimeout == 0 || player.Type != PlayerTypeHuman {
+		return nil
+	}
+
+	c := &decisionClock{
+		game:     g,
+		decision: decision,
+		input:    g.contextInput(g.PlayerInput(player)),
+		output:   g.PlayerOutput(player),
+		start:    time.Now(),
+	}
+	c.deadline = c.start.Add(g.DecisionTimeout + player.TimeBank)
+	c.ctx, c.cancel = context.WithDeadline(ctx, c.deadline)
+
+	fmt.Fprintf(c.output, "%s has %s to decide, %s in the time bank\n", player.Name, g.DecisionTimeout.Round(time.Second), player.TimeBank.Round(time.Second))
+
+	g.clock = c
+	return c
+}
+
+// stop ends the decision and takes any time used beyond the
+// decision's own time out of the player's time bank
+func (c *decisionClock) stop() {
+
+	c.cancel()
+	c.game.clock = nil
+
+	player := c.decision.Player
+	used := time.Since(c.start) - c.game.DecisionTimeout
+	if used > 0 {
+		player.TimeBank -= used
+		if player.TimeBank < 0 {
+			player.TimeBank = 0
+		}
+	}
+}
+
+// Read returns the player's answer, or the timeout play once time is up
+func (c *decisionClock) Read(p []byte) (int, error) {
+
+	if c.ctx.Err() == nil {
+		n, err := c.readCountingDown(p)
+		if err == nil || c.ctx.Err() == nil {
+			return n, err
+		}
+	}
+
+	if !c.expired {
+		c.expired = true
+		fmt.Fprintf(c.output, "\n%s is out of time\n", c.decision.Player.Name)
+	}
+
+	return copy(p, c.timeoutAnswer()+"\n"), nil
+}
+
+// readCountingDown waits for the player's answer, showing the time
+// left as it runs out
+func (c *decisionClock) readCountingDown(p []byte) (int, error) {
+
+	type result struct {
+		n   int
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		n, err := c.input.ReadContext(c.ctx, p)
+		done <- result{n, err}
+	}()
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case r := <-done:
+			return r.n, r.err
+		case <-ticker.C:
+			if !c.banking && c.decision.Player.TimeBank > 0 && time.Since(c.start) >= c.game.DecisionTimeout {
+				c.banking = true
+				fmt.Fprintf(c.output, "%s is on the time bank\n", c.decision.Player.Name)
+			}
+			left := int(time.Until(c.deadline).Round(time.Second).Seconds())
+			if countdownMarks[left] && left != c.shown {
+				c.shown = left
+				fmt.Fprintf(c.output, "(%ds left)\n", left)
+			}
+		}
+	}
+}
+
+// timeoutAnswer is the answer given for a player who is out of time.
+// Bets are kept at the table minimum, switch hands are kept and hands
+// are stood on or played by basic strategy
+func (c *decisionClock) timeoutAnswer() string {
+
+	g := c.game
+	player := c.decision.Player
+
+	switch player.Dialog {
+	case DialogBetOrQuit:
+		return "b"
+	case DialogPlaceYourBet:
+		return strconv.Itoa(player.MinBet())
+	case DialogSwitchOrKeep:
+		return "k"
+	}
+
+	if g.TimeoutPlay == TimeoutBasic && c.decision.Type == DecisionAction {
+		action := AiActionBasic(io.Discard, nil, player, c.decision.DealerCard, c.decision.HandIndex, g.CountFor(player), g.Stage)
+		for answer, a := range ActionMap {
+			if a != action {
+				continue
+			}
+			ok, _ := IsInputValid(answer, player)
+			if ok {
+				return answer
+			}
+		}
+	}
+
+	return "s"
+}
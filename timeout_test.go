@@ -0,0 +1,128 @@
+package blackjack_test
+
+import (
+	"blackjack"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mbarley333/cards"
+)
+
+// newTimedGame deals the human player 16 against a dealer 17 with a 5
+// to come.  The player bets 10 and then never answers again
+func newTimedGame(t *testing.T, opts ...blackjack.Option) (*blackjack.Game, *blackjack.Player, *strings.Builder) {
+
+	stack := []cards.Card{
+		{Rank: cards.Ten, Suit: cards.Club},
+		{Rank: cards.Seven, Suit: cards.Club},
+		{Rank: cards.Six, Suit: cards.Club},
+		{Rank: cards.King, Suit: cards.Spade},
+		{Rank: cards.Five, Suit: cards.Heart},
+	}
+
+	output := &strings.Builder{}
+	g, err := blackjack.NewBlackjackGame(append([]blackjack.Option{
+		blackjack.WithCustomDeck(cards.Deck{Cards: stack}),
+		blackjack.WithIncomingDeck(false),
+		blackjack.WithRendering(false),
+		blackjack.WithOutput(output),
+	}, opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reader, writer := io.Pipe()
+	t.Cleanup(func() {
+		writer.Close()
+	})
+	go writer.Write([]byte("b\n10\n"))
+
+	p := blackjack.HumanPlayer("Ironhide", 1)
+	p.Input = reader
+	g.AddPlayer(p)
+
+	return g, p, output
+}
+
+func TestDecisionTimeoutStands(t *testing.T) {
+	t.Parallel()
+
+	g, p, output := newTimedGame(t,
+		blackjack.WithDecisionTimeout(50*time.Millisecond),
+		blackjack.WithTimeBank(0),
+	)
+
+	err := g.PlayRound()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(output.String(), "Ironhide is out of time") {
+		t.Fatalf("want the player told they are out of time, got: %s", output.String())
+	}
+
+	want := 2
+	got := len(p.Hands[0].Cards)
+	if want != got {
+		t.Fatalf("want the player to stand on %d cards, got %d", want, got)
+	}
+
+	if p.Cash != 90 {
+		t.Fatalf("want the bet of 10 placed before the timeout lost, got cash %d", p.Cash)
+	}
+}
+
+func TestDecisionTimeoutPlaysBasicStrategy(t *testing.T) {
+	t.Parallel()
+
+	g, p, _ := newTimedGame(t,
+		blackjack.WithDecisionTimeout(50*time.Millisecond),
+		blackjack.WithTimeBank(0),
+		blackjack.WithTimeoutPlay(blackjack.TimeoutBasic),
+	)
+
+	err := g.PlayRound()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// basic strategy hits 16 against a 10 and then stands on 21
+	want := 3
+	got := len(p.Hands[0].Cards)
+	if want != got {
+		t.Fatalf("want the player to hit to %d cards, got %d", want, got)
+	}
+
+	if p.Cash != 110 {
+		t.Fatalf("want the hand won, got cash %d", p.Cash)
+	}
+}
+
+func TestTimeBankIsUsedUp(t *testing.T) {
+	t.Parallel()
+
+	g, p, _ := newTimedGame(t,
+		blackjack.WithDecisionTimeout(20*time.Millisecond),
+		blackjack.WithTimeBank(100*time.Millisecond),
+	)
+
+	if p.TimeBank != 100*time.Millisecond {
+		t.Fatalf("want the player seated with the table's time bank, got %s", p.TimeBank)
+	}
+
+	start := time.Now()
+	err := g.PlayRound()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if time.Since(start) < 100*time.Millisecond {
+		t.Fatalf("want the time bank waited out, round took %s", time.Since(start))
+	}
+
+	if p.TimeBank != 0 {
+		t.Fatalf("want the time bank used up, got %s", p.TimeBank)
+	}
+}
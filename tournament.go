@@ -0,0 +1,399 @@
+package blackjack
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Tournament plays blackjack in elimination rounds.  Every player starts
+// each round with the same chips and plays a fixed number of hands at a
+// table of their own.  The highest stacks at each table advance until
+// the last table plays the final round for the win.  The first player
+// to bet moves one place round the table each hand, so the betting
+// order on the final hand is shared out
+type Tournament struct {
+	Players     []*Player
+	Chips       int
+	Hands       int
+	TableSize   int
+	Advance     int
+	SecretBets  bool
+	Limits      BetLimits
+	Round       int
+	Leaderboard []Standing
+	opts        []Option
+	output      io.Writer
+	hand        int
+	advance     int
+	table       []*Player
+	bets        map[string]int
+}
+
+// Standing is how far a player got in the tournament.  Out is set for
+// a player who went broke or quit before their round was over
+type Standing struct {
+	Name   string
+	Round  int
+	Chips  int
+	Out    bool
+	Record Record
+}
+
+// NewTournament sets up a tournament for the players.  Options are
+// applied to the game at every table
+func NewTournament(output io.Writer, players []*Player, opts ...Option) *Tournament {
+	return &Tournament{
+		Players:   players,
+		Chips:     1000,
+		Hands:     10,
+		TableSize: 6,
+		Advance:   2,
+		Limits:    BetLimits{Min: 10, Max: 500},
+		opts:      opts,
+		output:    output,
+	}
+}
+
+// TournamentAiPlayer is a basic strategy player that bets for the
+// tournament standings rather than for each hand
+func (t *Tournament) TournamentAiPlayer(name string) *Player {
+	return &Player{
+		Name:   name,
+		Type:   PlayerTypeAiBasic,
+		Decide: AiActionBasic,
+		Bet:    t.AiBet,
+		Switch: AiSwitch,
+		Hands: []*Hand{
+			{Id: 1},
+		},
+	}
+}
+
+// Run plays elimination rounds until one player is left and shows the
+// leaderboard
+func (t *Tournament) Run() error {
+
+	if t.Advance < 1 || t.TableSize < 2 || t.Advance >= t.TableSize {
+		return fmt.Errorf("%d players advancing from tables of %d cannot eliminate anyone", t.Advance, t.TableSize)
+	}
+	if t.Hands < 1 {
+		return fmt.Errorf("a tournament round needs at least one hand")
+	}
+
+	remaining := t.Players
+	for _, player := range remaining {
+		player.Record = Record{}
+	}
+
+	for len(remaining) > 1 {
+		t.Round++
+		tables := t.Tables(remaining)
+
+		t.advance = t.Advance
+		if len(tables) == 1 {
+			t.advance = 1
+		}
+
+		next := []*Player{}
+		for i, table := range tables {
+			RenderStageMessage(t.output, fmt.Sprintf("ROUND %d TABLE %d", t.Round, i+1))
+			err := t.PlayTable(table)
+			if err != nil {
+				return err
+			}
+
+			ranked := t.Rank(table)
+			for place, player := range ranked {
+				if place < t.advance && player.Action != ActionQuit {
+					next = append(next, player)
+					continue
+				}
+				t.eliminate(player)
+			}
+		}
+
+		// everyone left the final table, they are on the leaderboard
+		if len(next) == 0 {
+			remaining = nil
+			break
+		}
+		remaining = next
+	}
+
+	// the winner goes first among players on equal chips
+	eliminated := t.Leaderboard
+	t.Leaderboard = nil
+	for _, player := range remaining {
+		t.eliminate(player)
+	}
+	t.Leaderboard = append(t.Leaderboard, eliminated...)
+
+	t.RenderLeaderboard(t.output)
+
+	return nil
+}
+
+// Tables splits the players into as few tables as will hold them, with
+// the players dealt round the tables in turn so the tables are even
+func (t *Tournament) Tables(players []*Player) [][]*Player {
+
+	count := (len(players) + t.TableSize - 1) / t.TableSize
+	tables := make([][]*Player, count)
+	for i, player := range players {
+		tables[i%count] = append(tables[i%count], player)
+	}
+
+	return tables
+}
+
+// PlayTable plays the round's hands at one table.  Players who go
+// broke or quit leave the table and are out
+func (t *Tournament) PlayTable(players []*Player) error {
+
+	opts := append(append([]Option{
+		WithOutput(t.output),
+		WithNumberOfHumanPlayers(0),
+	}, t.opts...),
+		WithSeatCount(0),
+		WithBetLimits(t.Limits.Min, t.Limits.Max),
+		WithSubscriber(t.notify),
+	)
+
+	g, err := NewBlackjackGame(opts...)
+	if err != nil {
+		return err
+	}
+
+	t.table = players
+	for _, player := range players {
+		player.Cash = t.Chips
+		player.Action = None
+		if player.Type == PlayerTypeHuman {
+			player.Bet = t.HumanBet
+		}
+		g.AddPlayer(player)
+	}
+
+	for t.hand = 1; t.hand <= t.Hands && g.PlayAgain(); t.hand++ {
+		t.rotate(g)
+		RenderStageMessage(t.output, fmt.Sprintf("HAND %d OF %d", t.hand, t.Hands))
+		t.RenderStandings(t.output)
+
+		err := g.PlayRound()
+		if err != nil {
+			return err
+		}
+	}
+	t.RenderStandings(t.output)
+
+	return nil
+}
+
+// rotate moves the first player to bet one place round the table
+func (t *Tournament) rotate(g *Game) {
+	if t.hand == 1 || len(g.Players) < 2 {
+		return
+	}
+	g.Players = append(g.Players[1:], g.Players[0])
+}
+
+// Rank sorts the table's players by their stacks, highest first.
+// Players still at the table rank above those who left it
+func (t *Tournament) Rank(players []*Player) []*Player {
+
+	ranked := append([]*Player{}, players...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		iOut, jOut := ranked[i].Action == ActionQuit, ranked[j].Action == ActionQuit
+		if iOut != jOut {
+			return jOut
+		}
+		return ranked[i].Cash > ranked[j].Cash
+	})
+
+	return ranked
+}
+
+func (t *Tournament) eliminate(player *Player) {
+	t.Leaderboard = append(t.Leaderboard, Standing{
+		Name:   player.Name,
+		Round:  t.Round,
+		Chips:  player.Cash,
+		Out:    player.Action == ActionQuit,
+		Record: player.Record,
+	})
+}
+
+// IsFinalHand is true while the last hand of the round is played
+func (t *Tournament) IsFinalHand() bool {
+	return t.hand == t.Hands
+}
+
+// notify keeps the bets placed on the current hand
+func (t *Tournament) notify(e Event) {
+	switch e.Type {
+	case EventRoundStarted, EventRoundEnded:
+		t.bets = map[string]int{}
+	case EventBetPlaced:
+		t.bets[e.Player] += e.Bet
+	}
+}
+
+// RenderStandings shows the chips of every player at the table
+func (t *Tournament) RenderStandings(output io.Writer) {
+
+	fmt.Fprintln(output, "Standings:")
+	for i, player := range t.Rank(t.table) {
+		line := strconv.Itoa(i+1) + ". " + player.Name + " $" + strconv.Itoa(player.Cash)
+		if player.Action == ActionQuit {
+			line += " (out)"
+		}
+		fmt.Fprintln(output, line)
+	}
+}
+
+// RenderBets shows what has been bet so far on the hand.  Bets on the
+// final hand are not shown when bets are secret
+func (t *Tournament) RenderBets(output io.Writer) {
+
+	if len(t.bets) == 0 {
+		return
+	}
+
+	bets := []string{}
+	for _, player := range t.table {
+		bet, ok := t.bets[player.Name]
+		if !ok {
+			continue
+		}
+		if t.SecretBets && t.IsFinalHand() {
+			bets = append(bets, player.Name+" (secret)")
+		} else {
+			bets = append(bets, player.Name+" $"+strconv.Itoa(bet))
+		}
+	}
+	fmt.Fprintln(output, "Bets placed: "+strings.Join(bets, ", "))
+}
+
+// HumanBet shows a human player the bets already placed before they
+// bet as at any other table
+func (t *Tournament) HumanBet(g *Game) error {
+
+	t.RenderBets(g.PlayerOutput(g.ActivePlayer))
+	return HumanBet(g)
+}
+
+// AiBet bets for the standings at the table.  The bets already placed
+// on the hand are taken into account unless they are secret
+func (t *Tournament) AiBet(g *Game) error {
+
+	player := g.ActivePlayer
+
+	rivals := []TournamentRival{}
+	for _, rival := range t.table {
+		if rival == player || rival.Action == ActionQuit {
+			continue
+		}
+		bet, ok := t.bets[rival.Name]
+		if t.SecretBets && t.IsFinalHand() {
+			ok = false
+		}
+		rivals = append(rivals, TournamentRival{Stack: rival.Cash + bet, Bet: bet, HasBet: ok})
+	}
+
+	bet := TournamentBet(player.Cash, rivals, t.advance, t.Hands-t.hand+1, player.MinBet(), player.MaxBet())
+	for _, index := range player.SpotIndexes() {
+		player.HandIndex = index
+		if player.MaxBet() >= bet {
+			player.PlaceBet(index, bet)
+		}
+	}
+	player.HandIndex = 0
+
+	return nil
+}
+
+// TournamentRival is another player at the table as a bettor sees
+// them.  A rival who has not bet yet, or whose bet is secret, could
+// bet as much as the table allows
+type TournamentRival struct {
+	Stack  int
+	Bet    int
+	HasBet bool
+}
+
+// TournamentBet is the bet that best keeps a stack of cash among the
+// players who advance.  With hands to come the gap to the cut is spread
+// over them.  On the final hand a player above the cut bets the least
+// that keeps them there whatever their closest rival does, and a player
+// below it bets what it takes to pass the rival at the cut
+func TournamentBet(cash int, rivals []TournamentRival, advance, handsLeft, minBet, maxBet int) int {
+
+	clamp := func(bet int) int {
+		if bet > maxBet {
+			bet = maxBet
+		}
+		if bet < minBet {
+			bet = minBet
+		}
+		return bet
+	}
+
+	if advance < 1 || len(rivals) < advance {
+		return clamp(minBet)
+	}
+
+	sort.SliceStable(rivals, func(i, j int) bool {
+		return rivals[i].Stack > rivals[j].Stack
+	})
+	cut := rivals[advance-1]
+
+	if cash > cut.Stack {
+		// the most the rival at the cut can end the hand with
+		win := maxBet
+		if cut.HasBet {
+			win = cut.Bet
+		} else if cut.Stack < win {
+			win = cut.Stack
+		}
+		threat := cut.Stack + win
+
+		if handsLeft > 1 {
+			return clamp(cash / 20)
+		}
+		if cash-minBet > threat {
+			return clamp(minBet)
+		}
+		return clamp(threat - cash + 1)
+	}
+
+	gap := cut.Stack - cash + 1
+	if handsLeft > 1 {
+		return clamp(gap/handsLeft + minBet)
+	}
+	return clamp(gap)
+}
+
+// RenderLeaderboard shows every player by how far they got, with the
+// winner first
+func (t *Tournament) RenderLeaderboard(output io.Writer) {
+
+	standings := append([]Standing{}, t.Leaderboard...)
+	sort.SliceStable(standings, func(i, j int) bool {
+		if standings[i].Round != standings[j].Round {
+			return standings[i].Round > standings[j].Round
+		}
+		if standings[i].Out != standings[j].Out {
+			return standings[j].Out
+		}
+		return standings[i].Chips > standings[j].Chips
+	})
+
+	fmt.Fprintln(output, "************** Tournament Leaderboard **************")
+	for i, standing := range standings {
+		fmt.Fprintf(output, "%d. %s, round %d, $%d, won: %d, lost: %d, tied: %d\n", i+1, standing.Name, standing.Round, standing.Chips,
+			standing.Record.Win, standing.Record.Lose, standing.Record.Tie)
+	}
+}
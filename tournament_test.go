@@ -0,0 +1,192 @@
+package blackjack_test
+
+import (
+	"blackjack"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestTournamentBet(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		description string
+		cash        int
+		rivals      []blackjack.TournamentRival
+		handsLeft   int
+		want        int
+	}
+
+	tcs := []testCase{
+		{
+			description: "leader with hands to come bets a twentieth of their stack",
+			cash:        1200,
+			rivals:      []blackjack.TournamentRival{{Stack: 1000}, {Stack: 900}},
+			handsLeft:   5,
+			want:        60,
+		},
+		{
+			description: "leader out of reach on the final hand bets the minimum",
+			cash:        1500,
+			rivals:      []blackjack.TournamentRival{{Stack: 900, Bet: 100, HasBet: true}, {Stack: 800}},
+			handsLeft:   1,
+			want:        10,
+		},
+		{
+			description: "leader on the final hand covers the rival who has bet",
+			cash:        1100,
+			rivals:      []blackjack.TournamentRival{{Stack: 1000, Bet: 200, HasBet: true}, {Stack: 800}},
+			handsLeft:   1,
+			want:        101,
+		},
+		{
+			description: "leader on the final hand covers a rival who could still bet the maximum",
+			cash:        1100,
+			rivals:      []blackjack.TournamentRival{{Stack: 1000}, {Stack: 800}},
+			handsLeft:   1,
+			want:        401,
+		},
+		{
+			description: "trailer with hands to come spreads the gap",
+			cash:        800,
+			rivals:      []blackjack.TournamentRival{{Stack: 1000}, {Stack: 900}},
+			handsLeft:   5,
+			want:        50,
+		},
+		{
+			description: "trailer on the final hand bets to pass the rival at the cut",
+			cash:        800,
+			rivals:      []blackjack.TournamentRival{{Stack: 1000}, {Stack: 900}},
+			handsLeft:   1,
+			want:        201,
+		},
+		{
+			description: "trailer far behind is held to the table maximum",
+			cash:        300,
+			rivals:      []blackjack.TournamentRival{{Stack: 1500}, {Stack: 1400}},
+			handsLeft:   1,
+			want:        500,
+		},
+	}
+
+	for _, tc := range tcs {
+		got := blackjack.TournamentBet(tc.cash, tc.rivals, 1, tc.handsLeft, 10, 500)
+		if tc.want != got {
+			t.Fatalf("%s: want %d, got %d", tc.description, tc.want, got)
+		}
+	}
+}
+
+func TestTournamentTables(t *testing.T) {
+	t.Parallel()
+
+	tournament := blackjack.NewTournament(&strings.Builder{}, nil)
+
+	players := []*blackjack.Player{}
+	for i := 0; i < 14; i++ {
+		players = append(players, tournament.TournamentAiPlayer("AiPlayer"+strconv.Itoa(i+1)))
+	}
+
+	tables := tournament.Tables(players)
+
+	want := []int{5, 5, 4}
+	if len(tables) != len(want) {
+		t.Fatalf("want %d tables, got %d", len(want), len(tables))
+	}
+	for i, table := range tables {
+		if len(table) != want[i] {
+			t.Fatalf("want %d players at table %d, got %d", want[i], i+1, len(table))
+		}
+	}
+}
+
+func TestTournamentRun(t *testing.T) {
+	t.Parallel()
+
+	output := &strings.Builder{}
+	tournament := blackjack.NewTournament(output, nil,
+		blackjack.WithSeed(47),
+		blackjack.WithIncomingDeck(false),
+		blackjack.WithRendering(false),
+	)
+	tournament.Hands = 3
+
+	for i := 0; i < 8; i++ {
+		tournament.Players = append(tournament.Players, tournament.TournamentAiPlayer("AiPlayer"+strconv.Itoa(i+1)))
+	}
+
+	err := tournament.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if tournament.Round != 2 {
+		t.Fatalf("want two tables of 4 then a final table, got %d rounds", tournament.Round)
+	}
+
+	if len(tournament.Leaderboard) != 8 {
+		t.Fatalf("want every player on the leaderboard, got %d", len(tournament.Leaderboard))
+	}
+
+	winner := tournament.Leaderboard[0]
+	if winner.Round != 2 || winner.Out {
+		t.Fatalf("want the winner to finish the final round, got %+v", winner)
+	}
+
+	finalists := 0
+	for _, standing := range tournament.Leaderboard {
+		if standing.Round == 2 {
+			finalists++
+		}
+	}
+	if finalists != 4 {
+		t.Fatalf("want two players from each table in the final, got %d", finalists)
+	}
+
+	if !strings.Contains(output.String(), "1. "+winner.Name+", round 2") {
+		t.Fatalf("want the winner top of the leaderboard, got: %s", output.String())
+	}
+}
+
+func TestTournamentEveryoneQuitsFinalTable(t *testing.T) {
+	t.Parallel()
+
+	tournament := blackjack.NewTournament(&strings.Builder{}, nil,
+		blackjack.WithSeed(47),
+		blackjack.WithIncomingDeck(false),
+		blackjack.WithRendering(false),
+	)
+	tournament.Hands = 2
+	tournament.TableSize = 3
+	tournament.Advance = 1
+
+	for i := 0; i < 4; i++ {
+		player := tournament.TournamentAiPlayer("AiPlayer" + strconv.Itoa(i+1))
+		player.Bet = func(g *blackjack.Game) error {
+			if tournament.Round == 2 {
+				g.ActivePlayer.Action = blackjack.ActionQuit
+				return nil
+			}
+			return tournament.AiBet(g)
+		}
+		tournament.Players = append(tournament.Players, player)
+	}
+
+	err := tournament.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(tournament.Leaderboard) != 4 {
+		t.Fatalf("want each player on the leaderboard once, got %d standings", len(tournament.Leaderboard))
+	}
+
+	seen := map[string]bool{}
+	for _, standing := range tournament.Leaderboard {
+		if seen[standing.Name] {
+			t.Fatalf("want %s on the leaderboard once, got: %+v", standing.Name, tournament.Leaderboard)
+		}
+		seen[standing.Name] = true
+	}
+}
@@ -0,0 +1,169 @@
+package blackjack
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// just enough of RFC 6455 to stream text messages to a browser
+
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+const (
+	opText  = 0x1
+	opClose = 0x8
+	opPing  = 0x9
+	opPong  = 0xA
+)
+
+// WebSocket is a server side websocket connection
+type WebSocket struct {
+	conn   net.Conn
+	reader *bufio.Reader
+	mu     sync.Mutex
+}
+
+func headerContains(h http.Header, name, value string) bool {
+	for _, v := range h.Values(name) {
+		for _, part := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// UpgradeWebSocket switches the request over to the websocket protocol
+func UpgradeWebSocket(w http.ResponseWriter, r *http.Request) (*WebSocket, error) {
+
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if !headerContains(r.Header, "Connection", "upgrade") || !headerContains(r.Header, "Upgrade", "websocket") || key == "" {
+		return nil, fmt.Errorf("not a websocket request")
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		return nil, fmt.Errorf("connection cannot be upgraded")
+	}
+
+	conn, buffered, err := hijacker.Hijack()
+	if err != nil {
+		return nil, fmt.Errorf("unable to upgrade connection, %s", err)
+	}
+
+	sum := sha1.Sum([]byte(key + websocketGUID))
+	accept := base64.StdEncoding.EncodeToString(sum[:])
+
+	fmt.Fprintf(buffered, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\n\r\n", accept)
+	err = buffered.Flush()
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("unable to upgrade connection, %s", err)
+	}
+
+	return &WebSocket{conn: conn, reader: buffered.Reader}, nil
+}
+
+func (ws *WebSocket) writeFrame(opcode byte, payload []byte) error {
+
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	header := []byte{0x80 | opcode}
+	switch {
+	case len(payload) < 126:
+		header = append(header, byte(len(payload)))
+	case len(payload) <= 0xFFFF:
+		header = append(header, 126, 0, 0)
+		binary.BigEndian.PutUint16(header[2:], uint16(len(payload)))
+	default:
+		header = append(header, 127, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint64(header[2:], uint64(len(payload)))
+	}
+
+	_, err := ws.conn.Write(append(header, payload...))
+	return err
+}
+
+// WriteText sends a text message
+func (ws *WebSocket) WriteText(message []byte) error {
+	return ws.writeFrame(opText, message)
+}
+
+// ReadMessage returns the next data message from the client, answering
+// pings along the way.  It returns io.EOF once the client closes
+func (ws *WebSocket) ReadMessage() ([]byte, error) {
+
+	for {
+		header := make([]byte, 2)
+		_, err := io.ReadFull(ws.reader, header)
+		if err != nil {
+			return nil, err
+		}
+
+		opcode := header[0] & 0x0F
+		masked := header[1]&0x80 != 0
+		length := uint64(header[1] & 0x7F)
+
+		switch length {
+		case 126:
+			extended := make([]byte, 2)
+			_, err = io.ReadFull(ws.reader, extended)
+			length = uint64(binary.BigEndian.Uint16(extended))
+		case 127:
+			extended := make([]byte, 8)
+			_, err = io.ReadFull(ws.reader, extended)
+			length = binary.BigEndian.Uint64(extended)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if length > 1<<20 {
+			return nil, fmt.Errorf("websocket message too large")
+		}
+
+		mask := make([]byte, 4)
+		if masked {
+			_, err = io.ReadFull(ws.reader, mask)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		payload := make([]byte, length)
+		_, err = io.ReadFull(ws.reader, payload)
+		if err != nil {
+			return nil, err
+		}
+		if masked {
+			for i := range payload {
+				payload[i] ^= mask[i%4]
+			}
+		}
+
+		switch opcode {
+		case opClose:
+			ws.writeFrame(opClose, nil)
+			return nil, io.EOF
+		case opPing:
+			ws.writeFrame(opPong, payload)
+		case opPong:
+		default:
+			return payload, nil
+		}
+	}
+}
+
+func (ws *WebSocket) Close() error {
+	ws.writeFrame(opClose, nil)
+	return ws.conn.Close()
+}